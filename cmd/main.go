@@ -15,6 +15,9 @@ import (
 	cfg "upgraded-calculator/internal/config"
 	calculatorGrpcServer "upgraded-calculator/internal/grpc"
 	calculatorHttpServer "upgraded-calculator/internal/http"
+	"upgraded-calculator/internal/programs"
+	"upgraded-calculator/internal/sessions"
+	"upgraded-calculator/internal/storage"
 )
 
 const (
@@ -64,9 +67,27 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	store, err := storage.Open(config)
+	if err != nil {
+		logger.Error("Failed to open session storage", "error", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+	sessionManager := sessions.NewManager(config, logger, store)
+	if err := sessionManager.Load(); err != nil {
+		logger.Error("Failed to load sessions", "error", err)
+		os.Exit(1)
+	}
+	go sessionManager.Run(ctx)
+	library := programs.NewLibrary(logger, store)
+	if err := library.Load(); err != nil {
+		logger.Error("Failed to load programs", "error", err)
+		os.Exit(1)
+	}
+
 	errChan := make(chan error, 2)
-	grpcServer := calculatorGrpcServer.CreateServer(config, logger)
-	httpServer := calculatorHttpServer.CreateServer(config, logger, ctx)
+	grpcServer := calculatorGrpcServer.CreateServer(config, logger, sessionManager, library)
+	httpServer := calculatorHttpServer.CreateServer(config, logger, ctx, sessionManager, library)
 
 	go func() {
 		lis, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", config.App.GRPCPort))
@@ -104,7 +125,7 @@ func main() {
 		go func() {
 			defer wg.Done()
 			if err := httpServer.Shutdown(shutdownCtx); err != nil {
-				logger.Error("HTTP shutdown error:", err)
+				logger.Error("HTTP shutdown error", "error", err)
 			} else {
 				logger.Info("HTTP Server stopped gracefully")
 			}
@@ -9,6 +9,7 @@ package gen
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -27,6 +28,9 @@ type Operand struct {
 	//
 	//	*Operand_Number
 	//	*Operand_Variable
+	//	*Operand_BigNumber
+	//	*Operand_FloatNumber
+	//	*Operand_DecimalNumber
 	Value         isOperand_Value `protobuf_oneof:"value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -87,6 +91,33 @@ func (x *Operand) GetVariable() string {
 	return ""
 }
 
+func (x *Operand) GetBigNumber() string {
+	if x != nil {
+		if x, ok := x.Value.(*Operand_BigNumber); ok {
+			return x.BigNumber
+		}
+	}
+	return ""
+}
+
+func (x *Operand) GetFloatNumber() float64 {
+	if x != nil {
+		if x, ok := x.Value.(*Operand_FloatNumber); ok {
+			return x.FloatNumber
+		}
+	}
+	return 0
+}
+
+func (x *Operand) GetDecimalNumber() string {
+	if x != nil {
+		if x, ok := x.Value.(*Operand_DecimalNumber); ok {
+			return x.DecimalNumber
+		}
+	}
+	return ""
+}
+
 type isOperand_Value interface {
 	isOperand_Value()
 }
@@ -99,17 +130,60 @@ type Operand_Variable struct {
 	Variable string `protobuf:"bytes,2,opt,name=variable,proto3,oneof"`
 }
 
+type Operand_BigNumber struct {
+	// Decimal integer that does not fit into int64, only for the "big" numbers mode.
+	BigNumber string `protobuf:"bytes,3,opt,name=big_number,json=bigNumber,proto3,oneof"`
+}
+
+type Operand_FloatNumber struct {
+	FloatNumber float64 `protobuf:"fixed64,4,opt,name=float_number,json=floatNumber,proto3,oneof"`
+}
+
+type Operand_DecimalNumber struct {
+	// Fractional literal such as "12.50", a fixed-point decimal in the
+	// "decimal" numbers mode and a double otherwise.
+	DecimalNumber string `protobuf:"bytes,5,opt,name=decimal_number,json=decimalNumber,proto3,oneof"`
+}
+
 func (*Operand_Number) isOperand_Value() {}
 
 func (*Operand_Variable) isOperand_Value() {}
 
+func (*Operand_BigNumber) isOperand_Value() {}
+
+func (*Operand_FloatNumber) isOperand_Value() {}
+
+func (*Operand_DecimalNumber) isOperand_Value() {}
+
 type Operation struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Op            *string                `protobuf:"bytes,2,opt,name=op,proto3,oneof" json:"op,omitempty"`
-	Var           string                 `protobuf:"bytes,3,opt,name=var,proto3" json:"var,omitempty"`
-	Left          *Operand               `protobuf:"bytes,4,opt,name=left,proto3,oneof" json:"left,omitempty"`
-	Right         *Operand               `protobuf:"bytes,5,opt,name=right,proto3,oneof" json:"right,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// Operator of a "calc" operation. The built-in binary operators are "+",
+	// "-", "*", "/", "%", "**", "&", "|", "^", "<<", ">>", "floordiv",
+	// "ceildiv" and the comparisons "==", "!=", "<", "<=", ">", ">=" giving 1
+	// or 0, the unary ones are "neg", "abs", "sign" and "sqrt", "sum" and
+	// "product" take one or more operands, "min" and "max" two or more; the
+	// server may register more. The complete list with operand
+	// kinds and error behaviour is served by the HTTP interface in
+	// /swagger.json (definition CalcAvailableOperation).
+	Op    *string  `protobuf:"bytes,2,opt,name=op,proto3,oneof" json:"op,omitempty"`
+	Var   string   `protobuf:"bytes,3,opt,name=var,proto3" json:"var,omitempty"`
+	Left  *Operand `protobuf:"bytes,4,opt,name=left,proto3,oneof" json:"left,omitempty"`
+	Right *Operand `protobuf:"bytes,5,opt,name=right,proto3,oneof" json:"right,omitempty"`
+	// Operands of operators of any arity such as "abs" or "sum", used instead
+	// of left and right.
+	Args []*Operand `protobuf:"bytes,6,rep,name=args,proto3" json:"args,omitempty"`
+	// Infix expression of an "expr" operation such as "(x + 3) * y / 2". It
+	// supports the binary operators above with the usual precedence,
+	// parentheses, unary minus, calls such as "max(a, b, 3)" and conditionals
+	// such as "x != 0 ? 10 / x : 0".
+	Expr *string `protobuf:"bytes,7,opt,name=expr,proto3,oneof" json:"expr,omitempty"`
+	// Operands of a "cond" operation: var is set to then if cond is non-zero
+	// and to else otherwise. Only the variable of the chosen branch is waited
+	// for.
+	Cond          *Operand `protobuf:"bytes,8,opt,name=cond,proto3,oneof" json:"cond,omitempty"`
+	Then          *Operand `protobuf:"bytes,9,opt,name=then,proto3,oneof" json:"then,omitempty"`
+	Else          *Operand `protobuf:"bytes,10,opt,name=else,proto3,oneof" json:"else,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -179,9 +253,79 @@ func (x *Operation) GetRight() *Operand {
 	return nil
 }
 
+func (x *Operation) GetArgs() []*Operand {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *Operation) GetExpr() string {
+	if x != nil && x.Expr != nil {
+		return *x.Expr
+	}
+	return ""
+}
+
+func (x *Operation) GetCond() *Operand {
+	if x != nil {
+		return x.Cond
+	}
+	return nil
+}
+
+func (x *Operation) GetThen() *Operand {
+	if x != nil {
+		return x.Then
+	}
+	return nil
+}
+
+func (x *Operation) GetElse() *Operand {
+	if x != nil {
+		return x.Else
+	}
+	return nil
+}
+
 type Request struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Operation     []*Operation           `protobuf:"bytes,1,rep,name=operation,proto3" json:"operation,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Operation []*Operation           `protobuf:"bytes,1,rep,name=operation,proto3" json:"operation,omitempty"`
+	// Order of printed variables in the response: "request" (default) lists
+	// them as print operations appear in the request, "completion" lists them
+	// as they were resolved and fills Variable.seq.
+	PrintOrder *string `protobuf:"bytes,2,opt,name=print_order,json=printOrder,proto3,oneof" json:"print_order,omitempty"`
+	// Numbers mode: "int64" (default), "big" for arbitrary-precision integers
+	// or "decimal" for fixed-point decimals. Doubles are available in every
+	// mode and win type promotion.
+	Numbers *string `protobuf:"bytes,3,opt,name=numbers,proto3,oneof" json:"numbers,omitempty"`
+	// Handling of int64 overflow: "wrapping" (default), "checked" fails the
+	// request, "saturating" clamps the result to the int64 bounds.
+	Overflow *string `protobuf:"bytes,4,opt,name=overflow,proto3,oneof" json:"overflow,omitempty"`
+	// Number of fractional digits kept in decimal results, 2 if unset.
+	DecimalScale *int32 `protobuf:"varint,5,opt,name=decimal_scale,json=decimalScale,proto3,oneof" json:"decimal_scale,omitempty"`
+	// Rounding of decimal results: "half_up" (default), "half_even", "down",
+	// "up", "floor" or "ceiling".
+	DecimalRounding *string `protobuf:"bytes,6,opt,name=decimal_rounding,json=decimalRounding,proto3,oneof" json:"decimal_rounding,omitempty"`
+	// Program in the line-based text format ("x = 3 + 8", "print x", "#"
+	// comments), used instead of operation.
+	Program *string `protobuf:"bytes,7,opt,name=program,proto3,oneof" json:"program,omitempty"`
+	// Evaluation mode: "eager" (default) computes every operation, "lazy"
+	// only those print operations depend on.
+	Evaluation *string `protobuf:"bytes,8,opt,name=evaluation,proto3,oneof" json:"evaluation,omitempty"`
+	// Errors mode: "fail_fast" (default) fails the request at the first failed
+	// operation, "collect" keeps computing independent operations and reports
+	// every failure in Response.errors.
+	Errors *string `protobuf:"bytes,9,opt,name=errors,proto3,oneof" json:"errors,omitempty"`
+	// Validation of operations: "strict" (default) rejects the request with
+	// InvalidArgument and a field violation per invalid operation, "lenient"
+	// drops invalid operations, executes the rest and lists the dropped ones
+	// in Response.dropped.
+	Validation *string `protobuf:"bytes,10,opt,name=validation,proto3,oneof" json:"validation,omitempty"`
+	// Number literals assigned to variables before the operations run, typed
+	// according to the numbers mode like operand literals. Operations read
+	// them like any variable but cannot assign them.
+	Inputs        map[string]*Operand `protobuf:"bytes,11,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -223,10 +367,88 @@ func (x *Request) GetOperation() []*Operation {
 	return nil
 }
 
+func (x *Request) GetPrintOrder() string {
+	if x != nil && x.PrintOrder != nil {
+		return *x.PrintOrder
+	}
+	return ""
+}
+
+func (x *Request) GetNumbers() string {
+	if x != nil && x.Numbers != nil {
+		return *x.Numbers
+	}
+	return ""
+}
+
+func (x *Request) GetOverflow() string {
+	if x != nil && x.Overflow != nil {
+		return *x.Overflow
+	}
+	return ""
+}
+
+func (x *Request) GetDecimalScale() int32 {
+	if x != nil && x.DecimalScale != nil {
+		return *x.DecimalScale
+	}
+	return 0
+}
+
+func (x *Request) GetDecimalRounding() string {
+	if x != nil && x.DecimalRounding != nil {
+		return *x.DecimalRounding
+	}
+	return ""
+}
+
+func (x *Request) GetProgram() string {
+	if x != nil && x.Program != nil {
+		return *x.Program
+	}
+	return ""
+}
+
+func (x *Request) GetEvaluation() string {
+	if x != nil && x.Evaluation != nil {
+		return *x.Evaluation
+	}
+	return ""
+}
+
+func (x *Request) GetErrors() string {
+	if x != nil && x.Errors != nil {
+		return *x.Errors
+	}
+	return ""
+}
+
+func (x *Request) GetValidation() string {
+	if x != nil && x.Validation != nil {
+		return *x.Validation
+	}
+	return ""
+}
+
+func (x *Request) GetInputs() map[string]*Operand {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
 type Variable struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Var           string                 `protobuf:"bytes,1,opt,name=var,proto3" json:"var,omitempty"`
-	Value         int64                  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Var   string                 `protobuf:"bytes,1,opt,name=var,proto3" json:"var,omitempty"`
+	Value int64                  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	// Sequence number of the print, set only for the "completion" print order.
+	Seq *int64 `protobuf:"varint,3,opt,name=seq,proto3,oneof" json:"seq,omitempty"`
+	// Decimal representation of the value, set instead of value in the "big" numbers mode.
+	BigValue *string `protobuf:"bytes,4,opt,name=big_value,json=bigValue,proto3,oneof" json:"big_value,omitempty"`
+	// Set instead of value for double results.
+	FloatValue *float64 `protobuf:"fixed64,5,opt,name=float_value,json=floatValue,proto3,oneof" json:"float_value,omitempty"`
+	// Set instead of value for decimal results.
+	DecimalValue  *string `protobuf:"bytes,6,opt,name=decimal_value,json=decimalValue,proto3,oneof" json:"decimal_value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -275,16 +497,176 @@ func (x *Variable) GetValue() int64 {
 	return 0
 }
 
+func (x *Variable) GetSeq() int64 {
+	if x != nil && x.Seq != nil {
+		return *x.Seq
+	}
+	return 0
+}
+
+func (x *Variable) GetBigValue() string {
+	if x != nil && x.BigValue != nil {
+		return *x.BigValue
+	}
+	return ""
+}
+
+func (x *Variable) GetFloatValue() float64 {
+	if x != nil && x.FloatValue != nil {
+		return *x.FloatValue
+	}
+	return 0
+}
+
+func (x *Variable) GetDecimalValue() string {
+	if x != nil && x.DecimalValue != nil {
+		return *x.DecimalValue
+	}
+	return ""
+}
+
+// Summary of the assignments of a program. Intermediate results of
+// expressions are not counted.
+type Summary struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Computed int64                  `protobuf:"varint,1,opt,name=computed,proto3" json:"computed,omitempty"`
+	// Variables that were not computed, as in the "lazy" evaluation mode.
+	Skipped       []string `protobuf:"bytes,2,rep,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Summary) Reset() {
+	*x = Summary{}
+	mi := &file_calculator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Summary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Summary) ProtoMessage() {}
+
+func (x *Summary) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Summary.ProtoReflect.Descriptor instead.
+func (*Summary) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Summary) GetComputed() int64 {
+	if x != nil {
+		return x.Computed
+	}
+	return 0
+}
+
+func (x *Summary) GetSkipped() []string {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+// Failure of an operation, reported in the "collect" errors mode.
+type OperationError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the operation in the request.
+	Index int32  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Var   string `protobuf:"bytes,2,opt,name=var,proto3" json:"var,omitempty"`
+	// Error code such as DIVISION_BY_ZERO, OVERFLOW or UPSTREAM_FAILED if a
+	// variable the operation depends on failed, see the ErrorCode definition
+	// in /swagger.json.
+	Code          string `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OperationError) Reset() {
+	*x = OperationError{}
+	mi := &file_calculator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperationError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationError) ProtoMessage() {}
+
+func (x *OperationError) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationError.ProtoReflect.Descriptor instead.
+func (*OperationError) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OperationError) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *OperationError) GetVar() string {
+	if x != nil {
+		return x.Var
+	}
+	return ""
+}
+
+func (x *OperationError) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *OperationError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 type Response struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Items         []*Variable            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Items   []*Variable            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Summary *Summary               `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	Errors  []*OperationError      `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	// Operations dropped by the "lenient" validation, indexed as in the request.
+	Dropped       []*OperationError `protobuf:"bytes,4,rep,name=dropped,proto3" json:"dropped,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Response) Reset() {
 	*x = Response{}
-	mi := &file_calculator_proto_msgTypes[4]
+	mi := &file_calculator_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -296,7 +678,7 @@ func (x *Response) String() string {
 func (*Response) ProtoMessage() {}
 
 func (x *Response) ProtoReflect() protoreflect.Message {
-	mi := &file_calculator_proto_msgTypes[4]
+	mi := &file_calculator_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -309,7 +691,7 @@ func (x *Response) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Response.ProtoReflect.Descriptor instead.
 func (*Response) Descriptor() ([]byte, []int) {
-	return file_calculator_proto_rawDescGZIP(), []int{4}
+	return file_calculator_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Response) GetItems() []*Variable {
@@ -319,87 +701,990 @@ func (x *Response) GetItems() []*Variable {
 	return nil
 }
 
-var File_calculator_proto protoreflect.FileDescriptor
+func (x *Response) GetSummary() *Summary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
 
-const file_calculator_proto_rawDesc = "" +
-	"\n" +
-	"\x10calculator.proto\x12\n" +
-	"calculator\"J\n" +
-	"\aOperand\x12\x18\n" +
-	"\x06number\x18\x01 \x01(\x03H\x00R\x06number\x12\x1c\n" +
-	"\bvariable\x18\x02 \x01(\tH\x00R\bvariableB\a\n" +
-	"\x05value\"\xbe\x01\n" +
-	"\tOperation\x12\x12\n" +
-	"\x04type\x18\x01 \x01(\tR\x04type\x12\x13\n" +
-	"\x02op\x18\x02 \x01(\tH\x00R\x02op\x88\x01\x01\x12\x10\n" +
-	"\x03var\x18\x03 \x01(\tR\x03var\x12,\n" +
-	"\x04left\x18\x04 \x01(\v2\x13.calculator.OperandH\x01R\x04left\x88\x01\x01\x12.\n" +
-	"\x05right\x18\x05 \x01(\v2\x13.calculator.OperandH\x02R\x05right\x88\x01\x01B\x05\n" +
-	"\x03_opB\a\n" +
-	"\x05_leftB\b\n" +
-	"\x06_right\">\n" +
-	"\aRequest\x123\n" +
-	"\toperation\x18\x01 \x03(\v2\x15.calculator.OperationR\toperation\"2\n" +
-	"\bVariable\x12\x10\n" +
-	"\x03var\x18\x01 \x01(\tR\x03var\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\x03R\x05value\"6\n" +
-	"\bResponse\x12*\n" +
-	"\x05items\x18\x01 \x03(\v2\x14.calculator.VariableR\x05items2B\n" +
-	"\n" +
-	"Calculator\x124\n" +
-	"\aExecute\x12\x13.calculator.Request\x1a\x14.calculator.ResponseB\x1fZ\x1dupgraded-calculator/proto/genb\x06proto3"
+func (x *Response) GetErrors() []*OperationError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
 
-var (
-	file_calculator_proto_rawDescOnce sync.Once
-	file_calculator_proto_rawDescData []byte
-)
+func (x *Response) GetDropped() []*OperationError {
+	if x != nil {
+		return x.Dropped
+	}
+	return nil
+}
 
-func file_calculator_proto_rawDescGZIP() []byte {
-	file_calculator_proto_rawDescOnce.Do(func() {
-		file_calculator_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_calculator_proto_rawDesc), len(file_calculator_proto_rawDesc)))
-	})
-	return file_calculator_proto_rawDescData
+// Message of the ExecuteStream and Session streams: a print or a failed
+// operation as soon as it happens, the operations dropped by the "lenient" validation
+// before anything is computed and the summary once the program is done.
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*Event_Variable
+	//	*Event_Error
+	//	*Event_Dropped
+	//	*Event_Summary
+	Event         isEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-var file_calculator_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
-var file_calculator_proto_goTypes = []any{
-	(*Operand)(nil),   // 0: calculator.Operand
-	(*Operation)(nil), // 1: calculator.Operation
-	(*Request)(nil),   // 2: calculator.Request
-	(*Variable)(nil),  // 3: calculator.Variable
-	(*Response)(nil),  // 4: calculator.Response
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_calculator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-var file_calculator_proto_depIdxs = []int32{
-	0, // 0: calculator.Operation.left:type_name -> calculator.Operand
-	0, // 1: calculator.Operation.right:type_name -> calculator.Operand
-	1, // 2: calculator.Request.operation:type_name -> calculator.Operation
-	3, // 3: calculator.Response.items:type_name -> calculator.Variable
-	2, // 4: calculator.Calculator.Execute:input_type -> calculator.Request
-	4, // 5: calculator.Calculator.Execute:output_type -> calculator.Response
-	5, // [5:6] is the sub-list for method output_type
-	4, // [4:5] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_calculator_proto_init() }
-func file_calculator_proto_init() {
-	if File_calculator_proto != nil {
-		return
-	}
-	file_calculator_proto_msgTypes[0].OneofWrappers = []any{
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Event) GetEvent() isEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *Event) GetVariable() *Variable {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Variable); ok {
+			return x.Variable
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetError() *OperationError {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetDropped() *OperationError {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Dropped); ok {
+			return x.Dropped
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetSummary() *Summary {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Summary); ok {
+			return x.Summary
+		}
+	}
+	return nil
+}
+
+type isEvent_Event interface {
+	isEvent_Event()
+}
+
+type Event_Variable struct {
+	Variable *Variable `protobuf:"bytes,1,opt,name=variable,proto3,oneof"`
+}
+
+type Event_Error struct {
+	Error *OperationError `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+type Event_Dropped struct {
+	Dropped *OperationError `protobuf:"bytes,3,opt,name=dropped,proto3,oneof"`
+}
+
+type Event_Summary struct {
+	Summary *Summary `protobuf:"bytes,4,opt,name=summary,proto3,oneof"`
+}
+
+func (*Event_Variable) isEvent_Event() {}
+
+func (*Event_Error) isEvent_Event() {}
+
+func (*Event_Dropped) isEvent_Event() {}
+
+func (*Event_Summary) isEvent_Event() {}
+
+// Session whose variables survive across ExecuteInSession calls. A session
+// expires once it has not been used for the configured TTL.
+type SessionInfo struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Variables of the session ordered by name, only set by GetSession.
+	Variables     []*Variable `protobuf:"bytes,4,rep,name=variables,proto3" json:"variables,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionInfo) Reset() {
+	*x = SessionInfo{}
+	mi := &file_calculator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionInfo) ProtoMessage() {}
+
+func (x *SessionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionInfo.ProtoReflect.Descriptor instead.
+func (*SessionInfo) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SessionInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SessionInfo) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *SessionInfo) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *SessionInfo) GetVariables() []*Variable {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+type CreateSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSessionRequest) Reset() {
+	*x = CreateSessionRequest{}
+	mi := &file_calculator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionRequest) ProtoMessage() {}
+
+func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{9}
+}
+
+type GetSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSessionRequest) Reset() {
+	*x = GetSessionRequest{}
+	mi := &file_calculator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSessionRequest) ProtoMessage() {}
+
+func (x *GetSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSessionRequest.ProtoReflect.Descriptor instead.
+func (*GetSessionRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type ExecuteInSessionRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SessionId string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// Executed like an Execute request with the variables of the session
+	// available. The variables it assigns are added to the session if it
+	// succeeds, variables of the session cannot be assigned again.
+	Request       *Request `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteInSessionRequest) Reset() {
+	*x = ExecuteInSessionRequest{}
+	mi := &file_calculator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteInSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteInSessionRequest) ProtoMessage() {}
+
+func (x *ExecuteInSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteInSessionRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteInSessionRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ExecuteInSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ExecuteInSessionRequest) GetRequest() *Request {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+type DeleteSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSessionRequest) Reset() {
+	*x = DeleteSessionRequest{}
+	mi := &file_calculator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSessionRequest) ProtoMessage() {}
+
+func (x *DeleteSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSessionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSessionRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type DeleteSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSessionResponse) Reset() {
+	*x = DeleteSessionResponse{}
+	mi := &file_calculator_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSessionResponse) ProtoMessage() {}
+
+func (x *DeleteSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSessionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSessionResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{13}
+}
+
+type SaveProgramRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Letters, digits, "_", "-" or ".", at most 64 characters.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Variables the program reads without assigning them. Every execution
+	// passes them in Request.inputs.
+	Inputs    []string     `protobuf:"bytes,2,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	Operation []*Operation `protobuf:"bytes,3,rep,name=operation,proto3" json:"operation,omitempty"`
+	// Program in the line-based text format, used instead of operation.
+	Program       *string `protobuf:"bytes,4,opt,name=program,proto3,oneof" json:"program,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveProgramRequest) Reset() {
+	*x = SaveProgramRequest{}
+	mi := &file_calculator_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveProgramRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveProgramRequest) ProtoMessage() {}
+
+func (x *SaveProgramRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveProgramRequest.ProtoReflect.Descriptor instead.
+func (*SaveProgramRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SaveProgramRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SaveProgramRequest) GetInputs() []string {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *SaveProgramRequest) GetOperation() []*Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return nil
+}
+
+func (x *SaveProgramRequest) GetProgram() string {
+	if x != nil && x.Program != nil {
+		return *x.Program
+	}
+	return ""
+}
+
+// Immutable version of a saved program, versions of a name count from 1.
+type ProgramVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Inputs        []string               `protobuf:"bytes,3,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProgramVersion) Reset() {
+	*x = ProgramVersion{}
+	mi := &file_calculator_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProgramVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProgramVersion) ProtoMessage() {}
+
+func (x *ProgramVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProgramVersion.ProtoReflect.Descriptor instead.
+func (*ProgramVersion) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ProgramVersion) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProgramVersion) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ProgramVersion) GetInputs() []string {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *ProgramVersion) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListProgramVersionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProgramVersionsRequest) Reset() {
+	*x = ListProgramVersionsRequest{}
+	mi := &file_calculator_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProgramVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProgramVersionsRequest) ProtoMessage() {}
+
+func (x *ListProgramVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProgramVersionsRequest.ProtoReflect.Descriptor instead.
+func (*ListProgramVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListProgramVersionsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ListProgramVersionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Versions      []*ProgramVersion      `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProgramVersionsResponse) Reset() {
+	*x = ListProgramVersionsResponse{}
+	mi := &file_calculator_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProgramVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProgramVersionsResponse) ProtoMessage() {}
+
+func (x *ListProgramVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProgramVersionsResponse.ProtoReflect.Descriptor instead.
+func (*ListProgramVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListProgramVersionsResponse) GetVersions() []*ProgramVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+type ExecuteSavedProgramRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Name    string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Options and inputs of the execution, operation and program must be
+	// unset. The inputs have to be exactly those of the program.
+	Request       *Request `protobuf:"bytes,3,opt,name=request,proto3" json:"request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteSavedProgramRequest) Reset() {
+	*x = ExecuteSavedProgramRequest{}
+	mi := &file_calculator_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteSavedProgramRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteSavedProgramRequest) ProtoMessage() {}
+
+func (x *ExecuteSavedProgramRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteSavedProgramRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteSavedProgramRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ExecuteSavedProgramRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExecuteSavedProgramRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ExecuteSavedProgramRequest) GetRequest() *Request {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+var File_calculator_proto protoreflect.FileDescriptor
+
+const file_calculator_proto_rawDesc = "" +
+	"\n" +
+	"\x10calculator.proto\x12\n" +
+	"calculator\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb9\x01\n" +
+	"\aOperand\x12\x18\n" +
+	"\x06number\x18\x01 \x01(\x03H\x00R\x06number\x12\x1c\n" +
+	"\bvariable\x18\x02 \x01(\tH\x00R\bvariable\x12\x1f\n" +
+	"\n" +
+	"big_number\x18\x03 \x01(\tH\x00R\tbigNumber\x12#\n" +
+	"\ffloat_number\x18\x04 \x01(\x01H\x00R\vfloatNumber\x12'\n" +
+	"\x0edecimal_number\x18\x05 \x01(\tH\x00R\rdecimalNumberB\a\n" +
+	"\x05value\"\xae\x03\n" +
+	"\tOperation\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x13\n" +
+	"\x02op\x18\x02 \x01(\tH\x00R\x02op\x88\x01\x01\x12\x10\n" +
+	"\x03var\x18\x03 \x01(\tR\x03var\x12,\n" +
+	"\x04left\x18\x04 \x01(\v2\x13.calculator.OperandH\x01R\x04left\x88\x01\x01\x12.\n" +
+	"\x05right\x18\x05 \x01(\v2\x13.calculator.OperandH\x02R\x05right\x88\x01\x01\x12'\n" +
+	"\x04args\x18\x06 \x03(\v2\x13.calculator.OperandR\x04args\x12\x17\n" +
+	"\x04expr\x18\a \x01(\tH\x03R\x04expr\x88\x01\x01\x12,\n" +
+	"\x04cond\x18\b \x01(\v2\x13.calculator.OperandH\x04R\x04cond\x88\x01\x01\x12,\n" +
+	"\x04then\x18\t \x01(\v2\x13.calculator.OperandH\x05R\x04then\x88\x01\x01\x12,\n" +
+	"\x04else\x18\n" +
+	" \x01(\v2\x13.calculator.OperandH\x06R\x04else\x88\x01\x01B\x05\n" +
+	"\x03_opB\a\n" +
+	"\x05_leftB\b\n" +
+	"\x06_rightB\a\n" +
+	"\x05_exprB\a\n" +
+	"\x05_condB\a\n" +
+	"\x05_thenB\a\n" +
+	"\x05_else\"\x92\x05\n" +
+	"\aRequest\x123\n" +
+	"\toperation\x18\x01 \x03(\v2\x15.calculator.OperationR\toperation\x12$\n" +
+	"\vprint_order\x18\x02 \x01(\tH\x00R\n" +
+	"printOrder\x88\x01\x01\x12\x1d\n" +
+	"\anumbers\x18\x03 \x01(\tH\x01R\anumbers\x88\x01\x01\x12\x1f\n" +
+	"\boverflow\x18\x04 \x01(\tH\x02R\boverflow\x88\x01\x01\x12(\n" +
+	"\rdecimal_scale\x18\x05 \x01(\x05H\x03R\fdecimalScale\x88\x01\x01\x12.\n" +
+	"\x10decimal_rounding\x18\x06 \x01(\tH\x04R\x0fdecimalRounding\x88\x01\x01\x12\x1d\n" +
+	"\aprogram\x18\a \x01(\tH\x05R\aprogram\x88\x01\x01\x12#\n" +
+	"\n" +
+	"evaluation\x18\b \x01(\tH\x06R\n" +
+	"evaluation\x88\x01\x01\x12\x1b\n" +
+	"\x06errors\x18\t \x01(\tH\aR\x06errors\x88\x01\x01\x12#\n" +
+	"\n" +
+	"validation\x18\n" +
+	" \x01(\tH\bR\n" +
+	"validation\x88\x01\x01\x127\n" +
+	"\x06inputs\x18\v \x03(\v2\x1f.calculator.Request.InputsEntryR\x06inputs\x1aN\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12)\n" +
+	"\x05value\x18\x02 \x01(\v2\x13.calculator.OperandR\x05value:\x028\x01B\x0e\n" +
+	"\f_print_orderB\n" +
+	"\n" +
+	"\b_numbersB\v\n" +
+	"\t_overflowB\x10\n" +
+	"\x0e_decimal_scaleB\x13\n" +
+	"\x11_decimal_roundingB\n" +
+	"\n" +
+	"\b_programB\r\n" +
+	"\v_evaluationB\t\n" +
+	"\a_errorsB\r\n" +
+	"\v_validation\"\xf3\x01\n" +
+	"\bVariable\x12\x10\n" +
+	"\x03var\x18\x01 \x01(\tR\x03var\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value\x12\x15\n" +
+	"\x03seq\x18\x03 \x01(\x03H\x00R\x03seq\x88\x01\x01\x12 \n" +
+	"\tbig_value\x18\x04 \x01(\tH\x01R\bbigValue\x88\x01\x01\x12$\n" +
+	"\vfloat_value\x18\x05 \x01(\x01H\x02R\n" +
+	"floatValue\x88\x01\x01\x12(\n" +
+	"\rdecimal_value\x18\x06 \x01(\tH\x03R\fdecimalValue\x88\x01\x01B\x06\n" +
+	"\x04_seqB\f\n" +
+	"\n" +
+	"_big_valueB\x0e\n" +
+	"\f_float_valueB\x10\n" +
+	"\x0e_decimal_value\"?\n" +
+	"\aSummary\x12\x1a\n" +
+	"\bcomputed\x18\x01 \x01(\x03R\bcomputed\x12\x18\n" +
+	"\askipped\x18\x02 \x03(\tR\askipped\"f\n" +
+	"\x0eOperationError\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x10\n" +
+	"\x03var\x18\x02 \x01(\tR\x03var\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"\xcf\x01\n" +
+	"\bResponse\x12*\n" +
+	"\x05items\x18\x01 \x03(\v2\x14.calculator.VariableR\x05items\x12-\n" +
+	"\asummary\x18\x02 \x01(\v2\x13.calculator.SummaryR\asummary\x122\n" +
+	"\x06errors\x18\x03 \x03(\v2\x1a.calculator.OperationErrorR\x06errors\x124\n" +
+	"\adropped\x18\x04 \x03(\v2\x1a.calculator.OperationErrorR\adropped\"\xe1\x01\n" +
+	"\x05Event\x122\n" +
+	"\bvariable\x18\x01 \x01(\v2\x14.calculator.VariableH\x00R\bvariable\x122\n" +
+	"\x05error\x18\x02 \x01(\v2\x1a.calculator.OperationErrorH\x00R\x05error\x126\n" +
+	"\adropped\x18\x03 \x01(\v2\x1a.calculator.OperationErrorH\x00R\adropped\x12/\n" +
+	"\asummary\x18\x04 \x01(\v2\x13.calculator.SummaryH\x00R\asummaryB\a\n" +
+	"\x05event\"\xc7\x01\n" +
+	"\vSessionInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x122\n" +
+	"\tvariables\x18\x04 \x03(\v2\x14.calculator.VariableR\tvariables\"\x16\n" +
+	"\x14CreateSessionRequest\"2\n" +
+	"\x11GetSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"g\n" +
+	"\x17ExecuteInSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12-\n" +
+	"\arequest\x18\x02 \x01(\v2\x13.calculator.RequestR\arequest\"5\n" +
+	"\x14DeleteSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"\x17\n" +
+	"\x15DeleteSessionResponse\"\xa0\x01\n" +
+	"\x12SaveProgramRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06inputs\x18\x02 \x03(\tR\x06inputs\x123\n" +
+	"\toperation\x18\x03 \x03(\v2\x15.calculator.OperationR\toperation\x12\x1d\n" +
+	"\aprogram\x18\x04 \x01(\tH\x00R\aprogram\x88\x01\x01B\n" +
+	"\n" +
+	"\b_program\"\x91\x01\n" +
+	"\x0eProgramVersion\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12\x16\n" +
+	"\x06inputs\x18\x03 \x03(\tR\x06inputs\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"0\n" +
+	"\x1aListProgramVersionsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"U\n" +
+	"\x1bListProgramVersionsResponse\x126\n" +
+	"\bversions\x18\x01 \x03(\v2\x1a.calculator.ProgramVersionR\bversions\"y\n" +
+	"\x1aExecuteSavedProgramRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12-\n" +
+	"\arequest\x18\x03 \x01(\v2\x13.calculator.RequestR\arequest2\xf5\x05\n" +
+	"\n" +
+	"Calculator\x124\n" +
+	"\aExecute\x12\x13.calculator.Request\x1a\x14.calculator.Response\x129\n" +
+	"\rExecuteStream\x12\x13.calculator.Request\x1a\x11.calculator.Event0\x01\x127\n" +
+	"\aSession\x12\x15.calculator.Operation\x1a\x11.calculator.Event(\x010\x01\x12J\n" +
+	"\rCreateSession\x12 .calculator.CreateSessionRequest\x1a\x17.calculator.SessionInfo\x12D\n" +
+	"\n" +
+	"GetSession\x12\x1d.calculator.GetSessionRequest\x1a\x17.calculator.SessionInfo\x12M\n" +
+	"\x10ExecuteInSession\x12#.calculator.ExecuteInSessionRequest\x1a\x14.calculator.Response\x12T\n" +
+	"\rDeleteSession\x12 .calculator.DeleteSessionRequest\x1a!.calculator.DeleteSessionResponse\x12I\n" +
+	"\vSaveProgram\x12\x1e.calculator.SaveProgramRequest\x1a\x1a.calculator.ProgramVersion\x12f\n" +
+	"\x13ListProgramVersions\x12&.calculator.ListProgramVersionsRequest\x1a'.calculator.ListProgramVersionsResponse\x12S\n" +
+	"\x13ExecuteSavedProgram\x12&.calculator.ExecuteSavedProgramRequest\x1a\x14.calculator.ResponseB\x1fZ\x1dupgraded-calculator/proto/genb\x06proto3"
+
+var (
+	file_calculator_proto_rawDescOnce sync.Once
+	file_calculator_proto_rawDescData []byte
+)
+
+func file_calculator_proto_rawDescGZIP() []byte {
+	file_calculator_proto_rawDescOnce.Do(func() {
+		file_calculator_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_calculator_proto_rawDesc), len(file_calculator_proto_rawDesc)))
+	})
+	return file_calculator_proto_rawDescData
+}
+
+var file_calculator_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_calculator_proto_goTypes = []any{
+	(*Operand)(nil),                     // 0: calculator.Operand
+	(*Operation)(nil),                   // 1: calculator.Operation
+	(*Request)(nil),                     // 2: calculator.Request
+	(*Variable)(nil),                    // 3: calculator.Variable
+	(*Summary)(nil),                     // 4: calculator.Summary
+	(*OperationError)(nil),              // 5: calculator.OperationError
+	(*Response)(nil),                    // 6: calculator.Response
+	(*Event)(nil),                       // 7: calculator.Event
+	(*SessionInfo)(nil),                 // 8: calculator.SessionInfo
+	(*CreateSessionRequest)(nil),        // 9: calculator.CreateSessionRequest
+	(*GetSessionRequest)(nil),           // 10: calculator.GetSessionRequest
+	(*ExecuteInSessionRequest)(nil),     // 11: calculator.ExecuteInSessionRequest
+	(*DeleteSessionRequest)(nil),        // 12: calculator.DeleteSessionRequest
+	(*DeleteSessionResponse)(nil),       // 13: calculator.DeleteSessionResponse
+	(*SaveProgramRequest)(nil),          // 14: calculator.SaveProgramRequest
+	(*ProgramVersion)(nil),              // 15: calculator.ProgramVersion
+	(*ListProgramVersionsRequest)(nil),  // 16: calculator.ListProgramVersionsRequest
+	(*ListProgramVersionsResponse)(nil), // 17: calculator.ListProgramVersionsResponse
+	(*ExecuteSavedProgramRequest)(nil),  // 18: calculator.ExecuteSavedProgramRequest
+	nil,                                 // 19: calculator.Request.InputsEntry
+	(*timestamppb.Timestamp)(nil),       // 20: google.protobuf.Timestamp
+}
+var file_calculator_proto_depIdxs = []int32{
+	0,  // 0: calculator.Operation.left:type_name -> calculator.Operand
+	0,  // 1: calculator.Operation.right:type_name -> calculator.Operand
+	0,  // 2: calculator.Operation.args:type_name -> calculator.Operand
+	0,  // 3: calculator.Operation.cond:type_name -> calculator.Operand
+	0,  // 4: calculator.Operation.then:type_name -> calculator.Operand
+	0,  // 5: calculator.Operation.else:type_name -> calculator.Operand
+	1,  // 6: calculator.Request.operation:type_name -> calculator.Operation
+	19, // 7: calculator.Request.inputs:type_name -> calculator.Request.InputsEntry
+	3,  // 8: calculator.Response.items:type_name -> calculator.Variable
+	4,  // 9: calculator.Response.summary:type_name -> calculator.Summary
+	5,  // 10: calculator.Response.errors:type_name -> calculator.OperationError
+	5,  // 11: calculator.Response.dropped:type_name -> calculator.OperationError
+	3,  // 12: calculator.Event.variable:type_name -> calculator.Variable
+	5,  // 13: calculator.Event.error:type_name -> calculator.OperationError
+	5,  // 14: calculator.Event.dropped:type_name -> calculator.OperationError
+	4,  // 15: calculator.Event.summary:type_name -> calculator.Summary
+	20, // 16: calculator.SessionInfo.created_at:type_name -> google.protobuf.Timestamp
+	20, // 17: calculator.SessionInfo.expires_at:type_name -> google.protobuf.Timestamp
+	3,  // 18: calculator.SessionInfo.variables:type_name -> calculator.Variable
+	2,  // 19: calculator.ExecuteInSessionRequest.request:type_name -> calculator.Request
+	1,  // 20: calculator.SaveProgramRequest.operation:type_name -> calculator.Operation
+	20, // 21: calculator.ProgramVersion.created_at:type_name -> google.protobuf.Timestamp
+	15, // 22: calculator.ListProgramVersionsResponse.versions:type_name -> calculator.ProgramVersion
+	2,  // 23: calculator.ExecuteSavedProgramRequest.request:type_name -> calculator.Request
+	0,  // 24: calculator.Request.InputsEntry.value:type_name -> calculator.Operand
+	2,  // 25: calculator.Calculator.Execute:input_type -> calculator.Request
+	2,  // 26: calculator.Calculator.ExecuteStream:input_type -> calculator.Request
+	1,  // 27: calculator.Calculator.Session:input_type -> calculator.Operation
+	9,  // 28: calculator.Calculator.CreateSession:input_type -> calculator.CreateSessionRequest
+	10, // 29: calculator.Calculator.GetSession:input_type -> calculator.GetSessionRequest
+	11, // 30: calculator.Calculator.ExecuteInSession:input_type -> calculator.ExecuteInSessionRequest
+	12, // 31: calculator.Calculator.DeleteSession:input_type -> calculator.DeleteSessionRequest
+	14, // 32: calculator.Calculator.SaveProgram:input_type -> calculator.SaveProgramRequest
+	16, // 33: calculator.Calculator.ListProgramVersions:input_type -> calculator.ListProgramVersionsRequest
+	18, // 34: calculator.Calculator.ExecuteSavedProgram:input_type -> calculator.ExecuteSavedProgramRequest
+	6,  // 35: calculator.Calculator.Execute:output_type -> calculator.Response
+	7,  // 36: calculator.Calculator.ExecuteStream:output_type -> calculator.Event
+	7,  // 37: calculator.Calculator.Session:output_type -> calculator.Event
+	8,  // 38: calculator.Calculator.CreateSession:output_type -> calculator.SessionInfo
+	8,  // 39: calculator.Calculator.GetSession:output_type -> calculator.SessionInfo
+	6,  // 40: calculator.Calculator.ExecuteInSession:output_type -> calculator.Response
+	13, // 41: calculator.Calculator.DeleteSession:output_type -> calculator.DeleteSessionResponse
+	15, // 42: calculator.Calculator.SaveProgram:output_type -> calculator.ProgramVersion
+	17, // 43: calculator.Calculator.ListProgramVersions:output_type -> calculator.ListProgramVersionsResponse
+	6,  // 44: calculator.Calculator.ExecuteSavedProgram:output_type -> calculator.Response
+	35, // [35:45] is the sub-list for method output_type
+	25, // [25:35] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_calculator_proto_init() }
+func file_calculator_proto_init() {
+	if File_calculator_proto != nil {
+		return
+	}
+	file_calculator_proto_msgTypes[0].OneofWrappers = []any{
 		(*Operand_Number)(nil),
 		(*Operand_Variable)(nil),
+		(*Operand_BigNumber)(nil),
+		(*Operand_FloatNumber)(nil),
+		(*Operand_DecimalNumber)(nil),
 	}
 	file_calculator_proto_msgTypes[1].OneofWrappers = []any{}
+	file_calculator_proto_msgTypes[2].OneofWrappers = []any{}
+	file_calculator_proto_msgTypes[3].OneofWrappers = []any{}
+	file_calculator_proto_msgTypes[7].OneofWrappers = []any{
+		(*Event_Variable)(nil),
+		(*Event_Error)(nil),
+		(*Event_Dropped)(nil),
+		(*Event_Summary)(nil),
+	}
+	file_calculator_proto_msgTypes[14].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_calculator_proto_rawDesc), len(file_calculator_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,16 +19,53 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Calculator_Execute_FullMethodName = "/calculator.Calculator/Execute"
+	Calculator_Execute_FullMethodName             = "/calculator.Calculator/Execute"
+	Calculator_ExecuteStream_FullMethodName       = "/calculator.Calculator/ExecuteStream"
+	Calculator_Session_FullMethodName             = "/calculator.Calculator/Session"
+	Calculator_CreateSession_FullMethodName       = "/calculator.Calculator/CreateSession"
+	Calculator_GetSession_FullMethodName          = "/calculator.Calculator/GetSession"
+	Calculator_ExecuteInSession_FullMethodName    = "/calculator.Calculator/ExecuteInSession"
+	Calculator_DeleteSession_FullMethodName       = "/calculator.Calculator/DeleteSession"
+	Calculator_SaveProgram_FullMethodName         = "/calculator.Calculator/SaveProgram"
+	Calculator_ListProgramVersions_FullMethodName = "/calculator.Calculator/ListProgramVersions"
+	Calculator_ExecuteSavedProgram_FullMethodName = "/calculator.Calculator/ExecuteSavedProgram"
 )
 
 // CalculatorClient is the client API for Calculator service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 //
-// Переделать на двунаправленные стримы
+// Failed requests are answered with a status carrying a
+// google.rpc.ErrorInfo detail, whose reason is the error code (the
+// ErrorCode definition in /swagger.json) and whose metadata holds the
+// "index" and "var" of the failed operation when known. Invalid requests
+// also carry a google.rpc.BadRequest detail pointing at the field at fault.
 type CalculatorClient interface {
 	Execute(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	// ExecuteStream runs the request like Execute and streams its results.
+	// Variables are sent in completion order whatever print_order says; in the
+	// "fail_fast" errors mode a failure ends the stream with an error status.
+	ExecuteStream(ctx context.Context, in *Request, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+	// Session runs operations as they arrive on a single calculator, so
+	// variables assigned earlier in the stream stay available. An operation
+	// runs as soon as the variables it reads are assigned, its prints and
+	// failures are sent right away with the index of the operation in the
+	// stream. Invalid operations are reported as errors and do not end the
+	// session. Once the client closes its side, operations still waiting for
+	// a variable fail with UNDEFINED_VARIABLE and the summary is sent.
+	Session(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Operation, Event], error)
+	// Named sessions keep their variables across requests. Unknown and
+	// expired sessions are NOT_FOUND, exceeding the number of sessions or the
+	// number of variables of a session is LIMIT_EXCEEDED.
+	CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*SessionInfo, error)
+	GetSession(ctx context.Context, in *GetSessionRequest, opts ...grpc.CallOption) (*SessionInfo, error)
+	ExecuteInSession(ctx context.Context, in *ExecuteInSessionRequest, opts ...grpc.CallOption) (*Response, error)
+	DeleteSession(ctx context.Context, in *DeleteSessionRequest, opts ...grpc.CallOption) (*DeleteSessionResponse, error)
+	// Saved programs are validated once when they are saved, every save adds
+	// a version. Unknown programs and versions are NOT_FOUND.
+	SaveProgram(ctx context.Context, in *SaveProgramRequest, opts ...grpc.CallOption) (*ProgramVersion, error)
+	ListProgramVersions(ctx context.Context, in *ListProgramVersionsRequest, opts ...grpc.CallOption) (*ListProgramVersionsResponse, error)
+	ExecuteSavedProgram(ctx context.Context, in *ExecuteSavedProgramRequest, opts ...grpc.CallOption) (*Response, error)
 }
 
 type calculatorClient struct {
@@ -49,13 +86,143 @@ func (c *calculatorClient) Execute(ctx context.Context, in *Request, opts ...grp
 	return out, nil
 }
 
+func (c *calculatorClient) ExecuteStream(ctx context.Context, in *Request, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Calculator_ServiceDesc.Streams[0], Calculator_ExecuteStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Request, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Calculator_ExecuteStreamClient = grpc.ServerStreamingClient[Event]
+
+func (c *calculatorClient) Session(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Operation, Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Calculator_ServiceDesc.Streams[1], Calculator_Session_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Operation, Event]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Calculator_SessionClient = grpc.BidiStreamingClient[Operation, Event]
+
+func (c *calculatorClient) CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*SessionInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionInfo)
+	err := c.cc.Invoke(ctx, Calculator_CreateSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) GetSession(ctx context.Context, in *GetSessionRequest, opts ...grpc.CallOption) (*SessionInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionInfo)
+	err := c.cc.Invoke(ctx, Calculator_GetSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) ExecuteInSession(ctx context.Context, in *ExecuteInSessionRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, Calculator_ExecuteInSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) DeleteSession(ctx context.Context, in *DeleteSessionRequest, opts ...grpc.CallOption) (*DeleteSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteSessionResponse)
+	err := c.cc.Invoke(ctx, Calculator_DeleteSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) SaveProgram(ctx context.Context, in *SaveProgramRequest, opts ...grpc.CallOption) (*ProgramVersion, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProgramVersion)
+	err := c.cc.Invoke(ctx, Calculator_SaveProgram_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) ListProgramVersions(ctx context.Context, in *ListProgramVersionsRequest, opts ...grpc.CallOption) (*ListProgramVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProgramVersionsResponse)
+	err := c.cc.Invoke(ctx, Calculator_ListProgramVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) ExecuteSavedProgram(ctx context.Context, in *ExecuteSavedProgramRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, Calculator_ExecuteSavedProgram_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CalculatorServer is the server API for Calculator service.
 // All implementations must embed UnimplementedCalculatorServer
 // for forward compatibility.
 //
-// Переделать на двунаправленные стримы
+// Failed requests are answered with a status carrying a
+// google.rpc.ErrorInfo detail, whose reason is the error code (the
+// ErrorCode definition in /swagger.json) and whose metadata holds the
+// "index" and "var" of the failed operation when known. Invalid requests
+// also carry a google.rpc.BadRequest detail pointing at the field at fault.
 type CalculatorServer interface {
 	Execute(context.Context, *Request) (*Response, error)
+	// ExecuteStream runs the request like Execute and streams its results.
+	// Variables are sent in completion order whatever print_order says; in the
+	// "fail_fast" errors mode a failure ends the stream with an error status.
+	ExecuteStream(*Request, grpc.ServerStreamingServer[Event]) error
+	// Session runs operations as they arrive on a single calculator, so
+	// variables assigned earlier in the stream stay available. An operation
+	// runs as soon as the variables it reads are assigned, its prints and
+	// failures are sent right away with the index of the operation in the
+	// stream. Invalid operations are reported as errors and do not end the
+	// session. Once the client closes its side, operations still waiting for
+	// a variable fail with UNDEFINED_VARIABLE and the summary is sent.
+	Session(grpc.BidiStreamingServer[Operation, Event]) error
+	// Named sessions keep their variables across requests. Unknown and
+	// expired sessions are NOT_FOUND, exceeding the number of sessions or the
+	// number of variables of a session is LIMIT_EXCEEDED.
+	CreateSession(context.Context, *CreateSessionRequest) (*SessionInfo, error)
+	GetSession(context.Context, *GetSessionRequest) (*SessionInfo, error)
+	ExecuteInSession(context.Context, *ExecuteInSessionRequest) (*Response, error)
+	DeleteSession(context.Context, *DeleteSessionRequest) (*DeleteSessionResponse, error)
+	// Saved programs are validated once when they are saved, every save adds
+	// a version. Unknown programs and versions are NOT_FOUND.
+	SaveProgram(context.Context, *SaveProgramRequest) (*ProgramVersion, error)
+	ListProgramVersions(context.Context, *ListProgramVersionsRequest) (*ListProgramVersionsResponse, error)
+	ExecuteSavedProgram(context.Context, *ExecuteSavedProgramRequest) (*Response, error)
 	mustEmbedUnimplementedCalculatorServer()
 }
 
@@ -69,6 +236,33 @@ type UnimplementedCalculatorServer struct{}
 func (UnimplementedCalculatorServer) Execute(context.Context, *Request) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Execute not implemented")
 }
+func (UnimplementedCalculatorServer) ExecuteStream(*Request, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method ExecuteStream not implemented")
+}
+func (UnimplementedCalculatorServer) Session(grpc.BidiStreamingServer[Operation, Event]) error {
+	return status.Errorf(codes.Unimplemented, "method Session not implemented")
+}
+func (UnimplementedCalculatorServer) CreateSession(context.Context, *CreateSessionRequest) (*SessionInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSession not implemented")
+}
+func (UnimplementedCalculatorServer) GetSession(context.Context, *GetSessionRequest) (*SessionInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSession not implemented")
+}
+func (UnimplementedCalculatorServer) ExecuteInSession(context.Context, *ExecuteInSessionRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteInSession not implemented")
+}
+func (UnimplementedCalculatorServer) DeleteSession(context.Context, *DeleteSessionRequest) (*DeleteSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSession not implemented")
+}
+func (UnimplementedCalculatorServer) SaveProgram(context.Context, *SaveProgramRequest) (*ProgramVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveProgram not implemented")
+}
+func (UnimplementedCalculatorServer) ListProgramVersions(context.Context, *ListProgramVersionsRequest) (*ListProgramVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProgramVersions not implemented")
+}
+func (UnimplementedCalculatorServer) ExecuteSavedProgram(context.Context, *ExecuteSavedProgramRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteSavedProgram not implemented")
+}
 func (UnimplementedCalculatorServer) mustEmbedUnimplementedCalculatorServer() {}
 func (UnimplementedCalculatorServer) testEmbeddedByValue()                    {}
 
@@ -108,6 +302,150 @@ func _Calculator_Execute_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Calculator_ExecuteStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Request)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CalculatorServer).ExecuteStream(m, &grpc.GenericServerStream[Request, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Calculator_ExecuteStreamServer = grpc.ServerStreamingServer[Event]
+
+func _Calculator_Session_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServer).Session(&grpc.GenericServerStream[Operation, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Calculator_SessionServer = grpc.BidiStreamingServer[Operation, Event]
+
+func _Calculator_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).CreateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_CreateSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).CreateSession(ctx, req.(*CreateSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_GetSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).GetSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_GetSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).GetSession(ctx, req.(*GetSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_ExecuteInSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteInSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).ExecuteInSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_ExecuteInSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).ExecuteInSession(ctx, req.(*ExecuteInSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_DeleteSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).DeleteSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_DeleteSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).DeleteSession(ctx, req.(*DeleteSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_SaveProgram_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveProgramRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).SaveProgram(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_SaveProgram_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).SaveProgram(ctx, req.(*SaveProgramRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_ListProgramVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProgramVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).ListProgramVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_ListProgramVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).ListProgramVersions(ctx, req.(*ListProgramVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_ExecuteSavedProgram_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteSavedProgramRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).ExecuteSavedProgram(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_ExecuteSavedProgram_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).ExecuteSavedProgram(ctx, req.(*ExecuteSavedProgramRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Calculator_ServiceDesc is the grpc.ServiceDesc for Calculator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -119,7 +457,47 @@ var Calculator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Execute",
 			Handler:    _Calculator_Execute_Handler,
 		},
+		{
+			MethodName: "CreateSession",
+			Handler:    _Calculator_CreateSession_Handler,
+		},
+		{
+			MethodName: "GetSession",
+			Handler:    _Calculator_GetSession_Handler,
+		},
+		{
+			MethodName: "ExecuteInSession",
+			Handler:    _Calculator_ExecuteInSession_Handler,
+		},
+		{
+			MethodName: "DeleteSession",
+			Handler:    _Calculator_DeleteSession_Handler,
+		},
+		{
+			MethodName: "SaveProgram",
+			Handler:    _Calculator_SaveProgram_Handler,
+		},
+		{
+			MethodName: "ListProgramVersions",
+			Handler:    _Calculator_ListProgramVersions_Handler,
+		},
+		{
+			MethodName: "ExecuteSavedProgram",
+			Handler:    _Calculator_ExecuteSavedProgram_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExecuteStream",
+			Handler:       _Calculator_ExecuteStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Session",
+			Handler:       _Calculator_Session_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "calculator.proto",
 }
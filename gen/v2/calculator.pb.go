@@ -0,0 +1,2047 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: v2/calculator.proto
+
+package genv2
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type OperationType int32
+
+const (
+	OperationType_OPERATION_TYPE_UNSPECIFIED OperationType = 0
+	OperationType_OPERATION_TYPE_CALC        OperationType = 1
+	OperationType_OPERATION_TYPE_PRINT       OperationType = 2
+	OperationType_OPERATION_TYPE_EXPR        OperationType = 3
+	OperationType_OPERATION_TYPE_COND        OperationType = 4
+)
+
+// Enum value maps for OperationType.
+var (
+	OperationType_name = map[int32]string{
+		0: "OPERATION_TYPE_UNSPECIFIED",
+		1: "OPERATION_TYPE_CALC",
+		2: "OPERATION_TYPE_PRINT",
+		3: "OPERATION_TYPE_EXPR",
+		4: "OPERATION_TYPE_COND",
+	}
+	OperationType_value = map[string]int32{
+		"OPERATION_TYPE_UNSPECIFIED": 0,
+		"OPERATION_TYPE_CALC":        1,
+		"OPERATION_TYPE_PRINT":       2,
+		"OPERATION_TYPE_EXPR":        3,
+		"OPERATION_TYPE_COND":        4,
+	}
+)
+
+func (x OperationType) Enum() *OperationType {
+	p := new(OperationType)
+	*p = x
+	return p
+}
+
+func (x OperationType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OperationType) Descriptor() protoreflect.EnumDescriptor {
+	return file_v2_calculator_proto_enumTypes[0].Descriptor()
+}
+
+func (OperationType) Type() protoreflect.EnumType {
+	return &file_v2_calculator_proto_enumTypes[0]
+}
+
+func (x OperationType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OperationType.Descriptor instead.
+func (OperationType) EnumDescriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{0}
+}
+
+// Operators of calc operations, see the CalcAvailableOperation definition in
+// /swagger.json for operand counts and error behaviour.
+type Operator int32
+
+const (
+	Operator_OPERATOR_UNSPECIFIED Operator = 0
+	Operator_OPERATOR_ADD         Operator = 1
+	Operator_OPERATOR_SUB         Operator = 2
+	Operator_OPERATOR_MUL         Operator = 3
+	Operator_OPERATOR_DIV         Operator = 4
+	Operator_OPERATOR_MOD         Operator = 5
+	Operator_OPERATOR_POW         Operator = 6
+	Operator_OPERATOR_AND         Operator = 7
+	Operator_OPERATOR_OR          Operator = 8
+	Operator_OPERATOR_XOR         Operator = 9
+	Operator_OPERATOR_SHL         Operator = 10
+	Operator_OPERATOR_SHR         Operator = 11
+	Operator_OPERATOR_EQ          Operator = 12
+	Operator_OPERATOR_NE          Operator = 13
+	Operator_OPERATOR_LT          Operator = 14
+	Operator_OPERATOR_LE          Operator = 15
+	Operator_OPERATOR_GT          Operator = 16
+	Operator_OPERATOR_GE          Operator = 17
+	Operator_OPERATOR_MIN         Operator = 18
+	Operator_OPERATOR_MAX         Operator = 19
+	Operator_OPERATOR_FLOORDIV    Operator = 20
+	Operator_OPERATOR_CEILDIV     Operator = 21
+	Operator_OPERATOR_SUM         Operator = 22
+	Operator_OPERATOR_PRODUCT     Operator = 23
+	Operator_OPERATOR_NEG         Operator = 24
+	Operator_OPERATOR_ABS         Operator = 25
+	Operator_OPERATOR_SIGN        Operator = 26
+	Operator_OPERATOR_SQRT        Operator = 27
+)
+
+// Enum value maps for Operator.
+var (
+	Operator_name = map[int32]string{
+		0:  "OPERATOR_UNSPECIFIED",
+		1:  "OPERATOR_ADD",
+		2:  "OPERATOR_SUB",
+		3:  "OPERATOR_MUL",
+		4:  "OPERATOR_DIV",
+		5:  "OPERATOR_MOD",
+		6:  "OPERATOR_POW",
+		7:  "OPERATOR_AND",
+		8:  "OPERATOR_OR",
+		9:  "OPERATOR_XOR",
+		10: "OPERATOR_SHL",
+		11: "OPERATOR_SHR",
+		12: "OPERATOR_EQ",
+		13: "OPERATOR_NE",
+		14: "OPERATOR_LT",
+		15: "OPERATOR_LE",
+		16: "OPERATOR_GT",
+		17: "OPERATOR_GE",
+		18: "OPERATOR_MIN",
+		19: "OPERATOR_MAX",
+		20: "OPERATOR_FLOORDIV",
+		21: "OPERATOR_CEILDIV",
+		22: "OPERATOR_SUM",
+		23: "OPERATOR_PRODUCT",
+		24: "OPERATOR_NEG",
+		25: "OPERATOR_ABS",
+		26: "OPERATOR_SIGN",
+		27: "OPERATOR_SQRT",
+	}
+	Operator_value = map[string]int32{
+		"OPERATOR_UNSPECIFIED": 0,
+		"OPERATOR_ADD":         1,
+		"OPERATOR_SUB":         2,
+		"OPERATOR_MUL":         3,
+		"OPERATOR_DIV":         4,
+		"OPERATOR_MOD":         5,
+		"OPERATOR_POW":         6,
+		"OPERATOR_AND":         7,
+		"OPERATOR_OR":          8,
+		"OPERATOR_XOR":         9,
+		"OPERATOR_SHL":         10,
+		"OPERATOR_SHR":         11,
+		"OPERATOR_EQ":          12,
+		"OPERATOR_NE":          13,
+		"OPERATOR_LT":          14,
+		"OPERATOR_LE":          15,
+		"OPERATOR_GT":          16,
+		"OPERATOR_GE":          17,
+		"OPERATOR_MIN":         18,
+		"OPERATOR_MAX":         19,
+		"OPERATOR_FLOORDIV":    20,
+		"OPERATOR_CEILDIV":     21,
+		"OPERATOR_SUM":         22,
+		"OPERATOR_PRODUCT":     23,
+		"OPERATOR_NEG":         24,
+		"OPERATOR_ABS":         25,
+		"OPERATOR_SIGN":        26,
+		"OPERATOR_SQRT":        27,
+	}
+)
+
+func (x Operator) Enum() *Operator {
+	p := new(Operator)
+	*p = x
+	return p
+}
+
+func (x Operator) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Operator) Descriptor() protoreflect.EnumDescriptor {
+	return file_v2_calculator_proto_enumTypes[1].Descriptor()
+}
+
+func (Operator) Type() protoreflect.EnumType {
+	return &file_v2_calculator_proto_enumTypes[1]
+}
+
+func (x Operator) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Operator.Descriptor instead.
+func (Operator) EnumDescriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{1}
+}
+
+type PrintOrder int32
+
+const (
+	// The "request" order.
+	PrintOrder_PRINT_ORDER_UNSPECIFIED PrintOrder = 0
+	PrintOrder_PRINT_ORDER_REQUEST     PrintOrder = 1
+	PrintOrder_PRINT_ORDER_COMPLETION  PrintOrder = 2
+)
+
+// Enum value maps for PrintOrder.
+var (
+	PrintOrder_name = map[int32]string{
+		0: "PRINT_ORDER_UNSPECIFIED",
+		1: "PRINT_ORDER_REQUEST",
+		2: "PRINT_ORDER_COMPLETION",
+	}
+	PrintOrder_value = map[string]int32{
+		"PRINT_ORDER_UNSPECIFIED": 0,
+		"PRINT_ORDER_REQUEST":     1,
+		"PRINT_ORDER_COMPLETION":  2,
+	}
+)
+
+func (x PrintOrder) Enum() *PrintOrder {
+	p := new(PrintOrder)
+	*p = x
+	return p
+}
+
+func (x PrintOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PrintOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_v2_calculator_proto_enumTypes[2].Descriptor()
+}
+
+func (PrintOrder) Type() protoreflect.EnumType {
+	return &file_v2_calculator_proto_enumTypes[2]
+}
+
+func (x PrintOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PrintOrder.Descriptor instead.
+func (PrintOrder) EnumDescriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{2}
+}
+
+type NumberMode int32
+
+const (
+	// The "int64" mode.
+	NumberMode_NUMBER_MODE_UNSPECIFIED NumberMode = 0
+	NumberMode_NUMBER_MODE_INT64       NumberMode = 1
+	NumberMode_NUMBER_MODE_BIG         NumberMode = 2
+	NumberMode_NUMBER_MODE_DECIMAL     NumberMode = 3
+)
+
+// Enum value maps for NumberMode.
+var (
+	NumberMode_name = map[int32]string{
+		0: "NUMBER_MODE_UNSPECIFIED",
+		1: "NUMBER_MODE_INT64",
+		2: "NUMBER_MODE_BIG",
+		3: "NUMBER_MODE_DECIMAL",
+	}
+	NumberMode_value = map[string]int32{
+		"NUMBER_MODE_UNSPECIFIED": 0,
+		"NUMBER_MODE_INT64":       1,
+		"NUMBER_MODE_BIG":         2,
+		"NUMBER_MODE_DECIMAL":     3,
+	}
+)
+
+func (x NumberMode) Enum() *NumberMode {
+	p := new(NumberMode)
+	*p = x
+	return p
+}
+
+func (x NumberMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NumberMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_v2_calculator_proto_enumTypes[3].Descriptor()
+}
+
+func (NumberMode) Type() protoreflect.EnumType {
+	return &file_v2_calculator_proto_enumTypes[3]
+}
+
+func (x NumberMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NumberMode.Descriptor instead.
+func (NumberMode) EnumDescriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{3}
+}
+
+type OverflowMode int32
+
+const (
+	// The "wrapping" mode.
+	OverflowMode_OVERFLOW_MODE_UNSPECIFIED OverflowMode = 0
+	OverflowMode_OVERFLOW_MODE_WRAPPING    OverflowMode = 1
+	OverflowMode_OVERFLOW_MODE_CHECKED     OverflowMode = 2
+	OverflowMode_OVERFLOW_MODE_SATURATING  OverflowMode = 3
+)
+
+// Enum value maps for OverflowMode.
+var (
+	OverflowMode_name = map[int32]string{
+		0: "OVERFLOW_MODE_UNSPECIFIED",
+		1: "OVERFLOW_MODE_WRAPPING",
+		2: "OVERFLOW_MODE_CHECKED",
+		3: "OVERFLOW_MODE_SATURATING",
+	}
+	OverflowMode_value = map[string]int32{
+		"OVERFLOW_MODE_UNSPECIFIED": 0,
+		"OVERFLOW_MODE_WRAPPING":    1,
+		"OVERFLOW_MODE_CHECKED":     2,
+		"OVERFLOW_MODE_SATURATING":  3,
+	}
+)
+
+func (x OverflowMode) Enum() *OverflowMode {
+	p := new(OverflowMode)
+	*p = x
+	return p
+}
+
+func (x OverflowMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OverflowMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_v2_calculator_proto_enumTypes[4].Descriptor()
+}
+
+func (OverflowMode) Type() protoreflect.EnumType {
+	return &file_v2_calculator_proto_enumTypes[4]
+}
+
+func (x OverflowMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OverflowMode.Descriptor instead.
+func (OverflowMode) EnumDescriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{4}
+}
+
+type RoundingMode int32
+
+const (
+	// The "half_up" mode.
+	RoundingMode_ROUNDING_MODE_UNSPECIFIED RoundingMode = 0
+	RoundingMode_ROUNDING_MODE_HALF_UP     RoundingMode = 1
+	RoundingMode_ROUNDING_MODE_HALF_EVEN   RoundingMode = 2
+	RoundingMode_ROUNDING_MODE_DOWN        RoundingMode = 3
+	RoundingMode_ROUNDING_MODE_UP          RoundingMode = 4
+	RoundingMode_ROUNDING_MODE_FLOOR       RoundingMode = 5
+	RoundingMode_ROUNDING_MODE_CEILING     RoundingMode = 6
+)
+
+// Enum value maps for RoundingMode.
+var (
+	RoundingMode_name = map[int32]string{
+		0: "ROUNDING_MODE_UNSPECIFIED",
+		1: "ROUNDING_MODE_HALF_UP",
+		2: "ROUNDING_MODE_HALF_EVEN",
+		3: "ROUNDING_MODE_DOWN",
+		4: "ROUNDING_MODE_UP",
+		5: "ROUNDING_MODE_FLOOR",
+		6: "ROUNDING_MODE_CEILING",
+	}
+	RoundingMode_value = map[string]int32{
+		"ROUNDING_MODE_UNSPECIFIED": 0,
+		"ROUNDING_MODE_HALF_UP":     1,
+		"ROUNDING_MODE_HALF_EVEN":   2,
+		"ROUNDING_MODE_DOWN":        3,
+		"ROUNDING_MODE_UP":          4,
+		"ROUNDING_MODE_FLOOR":       5,
+		"ROUNDING_MODE_CEILING":     6,
+	}
+)
+
+func (x RoundingMode) Enum() *RoundingMode {
+	p := new(RoundingMode)
+	*p = x
+	return p
+}
+
+func (x RoundingMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RoundingMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_v2_calculator_proto_enumTypes[5].Descriptor()
+}
+
+func (RoundingMode) Type() protoreflect.EnumType {
+	return &file_v2_calculator_proto_enumTypes[5]
+}
+
+func (x RoundingMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RoundingMode.Descriptor instead.
+func (RoundingMode) EnumDescriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{5}
+}
+
+type EvaluationMode int32
+
+const (
+	// The "eager" mode.
+	EvaluationMode_EVALUATION_MODE_UNSPECIFIED EvaluationMode = 0
+	EvaluationMode_EVALUATION_MODE_EAGER       EvaluationMode = 1
+	EvaluationMode_EVALUATION_MODE_LAZY        EvaluationMode = 2
+)
+
+// Enum value maps for EvaluationMode.
+var (
+	EvaluationMode_name = map[int32]string{
+		0: "EVALUATION_MODE_UNSPECIFIED",
+		1: "EVALUATION_MODE_EAGER",
+		2: "EVALUATION_MODE_LAZY",
+	}
+	EvaluationMode_value = map[string]int32{
+		"EVALUATION_MODE_UNSPECIFIED": 0,
+		"EVALUATION_MODE_EAGER":       1,
+		"EVALUATION_MODE_LAZY":        2,
+	}
+)
+
+func (x EvaluationMode) Enum() *EvaluationMode {
+	p := new(EvaluationMode)
+	*p = x
+	return p
+}
+
+func (x EvaluationMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EvaluationMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_v2_calculator_proto_enumTypes[6].Descriptor()
+}
+
+func (EvaluationMode) Type() protoreflect.EnumType {
+	return &file_v2_calculator_proto_enumTypes[6]
+}
+
+func (x EvaluationMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EvaluationMode.Descriptor instead.
+func (EvaluationMode) EnumDescriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{6}
+}
+
+type ErrorMode int32
+
+const (
+	// The "fail_fast" mode.
+	ErrorMode_ERROR_MODE_UNSPECIFIED ErrorMode = 0
+	ErrorMode_ERROR_MODE_FAIL_FAST   ErrorMode = 1
+	ErrorMode_ERROR_MODE_COLLECT     ErrorMode = 2
+)
+
+// Enum value maps for ErrorMode.
+var (
+	ErrorMode_name = map[int32]string{
+		0: "ERROR_MODE_UNSPECIFIED",
+		1: "ERROR_MODE_FAIL_FAST",
+		2: "ERROR_MODE_COLLECT",
+	}
+	ErrorMode_value = map[string]int32{
+		"ERROR_MODE_UNSPECIFIED": 0,
+		"ERROR_MODE_FAIL_FAST":   1,
+		"ERROR_MODE_COLLECT":     2,
+	}
+)
+
+func (x ErrorMode) Enum() *ErrorMode {
+	p := new(ErrorMode)
+	*p = x
+	return p
+}
+
+func (x ErrorMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_v2_calculator_proto_enumTypes[7].Descriptor()
+}
+
+func (ErrorMode) Type() protoreflect.EnumType {
+	return &file_v2_calculator_proto_enumTypes[7]
+}
+
+func (x ErrorMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorMode.Descriptor instead.
+func (ErrorMode) EnumDescriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{7}
+}
+
+// Stable error codes, the ErrorCode definition in /swagger.json with the
+// ERROR_CODE_ prefix.
+type ErrorCode int32
+
+const (
+	ErrorCode_ERROR_CODE_UNSPECIFIED          ErrorCode = 0
+	ErrorCode_ERROR_CODE_INVALID_REQUEST      ErrorCode = 1
+	ErrorCode_ERROR_CODE_INVALID_OPERATION    ErrorCode = 2
+	ErrorCode_ERROR_CODE_INVALID_OPERAND      ErrorCode = 3
+	ErrorCode_ERROR_CODE_SYNTAX_ERROR         ErrorCode = 4
+	ErrorCode_ERROR_CODE_UNDEFINED_VARIABLE   ErrorCode = 5
+	ErrorCode_ERROR_CODE_DUPLICATE_ASSIGNMENT ErrorCode = 6
+	ErrorCode_ERROR_CODE_CYCLE                ErrorCode = 7
+	ErrorCode_ERROR_CODE_DIVISION_BY_ZERO     ErrorCode = 8
+	ErrorCode_ERROR_CODE_OVERFLOW             ErrorCode = 9
+	ErrorCode_ERROR_CODE_UPSTREAM_FAILED      ErrorCode = 10
+	ErrorCode_ERROR_CODE_EVALUATION_ERROR     ErrorCode = 11
+	ErrorCode_ERROR_CODE_TIMEOUT              ErrorCode = 12
+	ErrorCode_ERROR_CODE_CANCELLED            ErrorCode = 13
+	ErrorCode_ERROR_CODE_INTERNAL             ErrorCode = 14
+	ErrorCode_ERROR_CODE_NOT_FOUND            ErrorCode = 15
+	ErrorCode_ERROR_CODE_LIMIT_EXCEEDED       ErrorCode = 16
+)
+
+// Enum value maps for ErrorCode.
+var (
+	ErrorCode_name = map[int32]string{
+		0:  "ERROR_CODE_UNSPECIFIED",
+		1:  "ERROR_CODE_INVALID_REQUEST",
+		2:  "ERROR_CODE_INVALID_OPERATION",
+		3:  "ERROR_CODE_INVALID_OPERAND",
+		4:  "ERROR_CODE_SYNTAX_ERROR",
+		5:  "ERROR_CODE_UNDEFINED_VARIABLE",
+		6:  "ERROR_CODE_DUPLICATE_ASSIGNMENT",
+		7:  "ERROR_CODE_CYCLE",
+		8:  "ERROR_CODE_DIVISION_BY_ZERO",
+		9:  "ERROR_CODE_OVERFLOW",
+		10: "ERROR_CODE_UPSTREAM_FAILED",
+		11: "ERROR_CODE_EVALUATION_ERROR",
+		12: "ERROR_CODE_TIMEOUT",
+		13: "ERROR_CODE_CANCELLED",
+		14: "ERROR_CODE_INTERNAL",
+		15: "ERROR_CODE_NOT_FOUND",
+		16: "ERROR_CODE_LIMIT_EXCEEDED",
+	}
+	ErrorCode_value = map[string]int32{
+		"ERROR_CODE_UNSPECIFIED":          0,
+		"ERROR_CODE_INVALID_REQUEST":      1,
+		"ERROR_CODE_INVALID_OPERATION":    2,
+		"ERROR_CODE_INVALID_OPERAND":      3,
+		"ERROR_CODE_SYNTAX_ERROR":         4,
+		"ERROR_CODE_UNDEFINED_VARIABLE":   5,
+		"ERROR_CODE_DUPLICATE_ASSIGNMENT": 6,
+		"ERROR_CODE_CYCLE":                7,
+		"ERROR_CODE_DIVISION_BY_ZERO":     8,
+		"ERROR_CODE_OVERFLOW":             9,
+		"ERROR_CODE_UPSTREAM_FAILED":      10,
+		"ERROR_CODE_EVALUATION_ERROR":     11,
+		"ERROR_CODE_TIMEOUT":              12,
+		"ERROR_CODE_CANCELLED":            13,
+		"ERROR_CODE_INTERNAL":             14,
+		"ERROR_CODE_NOT_FOUND":            15,
+		"ERROR_CODE_LIMIT_EXCEEDED":       16,
+	}
+)
+
+func (x ErrorCode) Enum() *ErrorCode {
+	p := new(ErrorCode)
+	*p = x
+	return p
+}
+
+func (x ErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_v2_calculator_proto_enumTypes[8].Descriptor()
+}
+
+func (ErrorCode) Type() protoreflect.EnumType {
+	return &file_v2_calculator_proto_enumTypes[8]
+}
+
+func (x ErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorCode.Descriptor instead.
+func (ErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{8}
+}
+
+type Operand struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*Operand_Number
+	//	*Operand_Variable
+	//	*Operand_BigNumber
+	//	*Operand_FloatNumber
+	//	*Operand_DecimalNumber
+	Value         isOperand_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Operand) Reset() {
+	*x = Operand{}
+	mi := &file_v2_calculator_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Operand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operand) ProtoMessage() {}
+
+func (x *Operand) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_calculator_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operand.ProtoReflect.Descriptor instead.
+func (*Operand) Descriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Operand) GetValue() isOperand_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Operand) GetNumber() int64 {
+	if x != nil {
+		if x, ok := x.Value.(*Operand_Number); ok {
+			return x.Number
+		}
+	}
+	return 0
+}
+
+func (x *Operand) GetVariable() string {
+	if x != nil {
+		if x, ok := x.Value.(*Operand_Variable); ok {
+			return x.Variable
+		}
+	}
+	return ""
+}
+
+func (x *Operand) GetBigNumber() string {
+	if x != nil {
+		if x, ok := x.Value.(*Operand_BigNumber); ok {
+			return x.BigNumber
+		}
+	}
+	return ""
+}
+
+func (x *Operand) GetFloatNumber() float64 {
+	if x != nil {
+		if x, ok := x.Value.(*Operand_FloatNumber); ok {
+			return x.FloatNumber
+		}
+	}
+	return 0
+}
+
+func (x *Operand) GetDecimalNumber() string {
+	if x != nil {
+		if x, ok := x.Value.(*Operand_DecimalNumber); ok {
+			return x.DecimalNumber
+		}
+	}
+	return ""
+}
+
+type isOperand_Value interface {
+	isOperand_Value()
+}
+
+type Operand_Number struct {
+	Number int64 `protobuf:"varint,1,opt,name=number,proto3,oneof"`
+}
+
+type Operand_Variable struct {
+	Variable string `protobuf:"bytes,2,opt,name=variable,proto3,oneof"`
+}
+
+type Operand_BigNumber struct {
+	// Decimal integer that does not fit into int64, only for the big numbers mode.
+	BigNumber string `protobuf:"bytes,3,opt,name=big_number,json=bigNumber,proto3,oneof"`
+}
+
+type Operand_FloatNumber struct {
+	FloatNumber float64 `protobuf:"fixed64,4,opt,name=float_number,json=floatNumber,proto3,oneof"`
+}
+
+type Operand_DecimalNumber struct {
+	// Fractional literal such as "12.50", a fixed-point decimal in the
+	// decimal numbers mode and a double otherwise.
+	DecimalNumber string `protobuf:"bytes,5,opt,name=decimal_number,json=decimalNumber,proto3,oneof"`
+}
+
+func (*Operand_Number) isOperand_Value() {}
+
+func (*Operand_Variable) isOperand_Value() {}
+
+func (*Operand_BigNumber) isOperand_Value() {}
+
+func (*Operand_FloatNumber) isOperand_Value() {}
+
+func (*Operand_DecimalNumber) isOperand_Value() {}
+
+// Calc assigns the result of the operator applied to the operands in order,
+// the operand count is validated against the operator arity.
+type Calc struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Op            Operator               `protobuf:"varint,1,opt,name=op,proto3,enum=calculator.v2.Operator" json:"op,omitempty"`
+	Args          []*Operand             `protobuf:"bytes,2,rep,name=args,proto3" json:"args,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Calc) Reset() {
+	*x = Calc{}
+	mi := &file_v2_calculator_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Calc) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Calc) ProtoMessage() {}
+
+func (x *Calc) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_calculator_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Calc.ProtoReflect.Descriptor instead.
+func (*Calc) Descriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Calc) GetOp() Operator {
+	if x != nil {
+		return x.Op
+	}
+	return Operator_OPERATOR_UNSPECIFIED
+}
+
+func (x *Calc) GetArgs() []*Operand {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+// Print prints the variable of the operation.
+type Print struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Print) Reset() {
+	*x = Print{}
+	mi := &file_v2_calculator_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Print) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Print) ProtoMessage() {}
+
+func (x *Print) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_calculator_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Print.ProtoReflect.Descriptor instead.
+func (*Print) Descriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{2}
+}
+
+// Expr assigns the result of an infix expression such as "(x + 3) * y / 2".
+type Expr struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Expr          string                 `protobuf:"bytes,1,opt,name=expr,proto3" json:"expr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Expr) Reset() {
+	*x = Expr{}
+	mi := &file_v2_calculator_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Expr) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Expr) ProtoMessage() {}
+
+func (x *Expr) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_calculator_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Expr.ProtoReflect.Descriptor instead.
+func (*Expr) Descriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Expr) GetExpr() string {
+	if x != nil {
+		return x.Expr
+	}
+	return ""
+}
+
+// Cond assigns then if cond is non-zero and else otherwise. Only the
+// variable of the chosen branch is waited for.
+type Cond struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cond          *Operand               `protobuf:"bytes,1,opt,name=cond,proto3" json:"cond,omitempty"`
+	Then          *Operand               `protobuf:"bytes,2,opt,name=then,proto3" json:"then,omitempty"`
+	Else          *Operand               `protobuf:"bytes,3,opt,name=else,proto3" json:"else,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cond) Reset() {
+	*x = Cond{}
+	mi := &file_v2_calculator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cond) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cond) ProtoMessage() {}
+
+func (x *Cond) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_calculator_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cond.ProtoReflect.Descriptor instead.
+func (*Cond) Descriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Cond) GetCond() *Operand {
+	if x != nil {
+		return x.Cond
+	}
+	return nil
+}
+
+func (x *Cond) GetThen() *Operand {
+	if x != nil {
+		return x.Then
+	}
+	return nil
+}
+
+func (x *Cond) GetElse() *Operand {
+	if x != nil {
+		return x.Else
+	}
+	return nil
+}
+
+type Operation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional, the body determines the type. If set it must match the body.
+	Type OperationType `protobuf:"varint,1,opt,name=type,proto3,enum=calculator.v2.OperationType" json:"type,omitempty"`
+	Var  string        `protobuf:"bytes,2,opt,name=var,proto3" json:"var,omitempty"`
+	// Types that are valid to be assigned to Body:
+	//
+	//	*Operation_Calc
+	//	*Operation_Print
+	//	*Operation_Expr
+	//	*Operation_Cond
+	Body          isOperation_Body `protobuf_oneof:"body"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Operation) Reset() {
+	*x = Operation{}
+	mi := &file_v2_calculator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Operation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operation) ProtoMessage() {}
+
+func (x *Operation) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_calculator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operation.ProtoReflect.Descriptor instead.
+func (*Operation) Descriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Operation) GetType() OperationType {
+	if x != nil {
+		return x.Type
+	}
+	return OperationType_OPERATION_TYPE_UNSPECIFIED
+}
+
+func (x *Operation) GetVar() string {
+	if x != nil {
+		return x.Var
+	}
+	return ""
+}
+
+func (x *Operation) GetBody() isOperation_Body {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *Operation) GetCalc() *Calc {
+	if x != nil {
+		if x, ok := x.Body.(*Operation_Calc); ok {
+			return x.Calc
+		}
+	}
+	return nil
+}
+
+func (x *Operation) GetPrint() *Print {
+	if x != nil {
+		if x, ok := x.Body.(*Operation_Print); ok {
+			return x.Print
+		}
+	}
+	return nil
+}
+
+func (x *Operation) GetExpr() *Expr {
+	if x != nil {
+		if x, ok := x.Body.(*Operation_Expr); ok {
+			return x.Expr
+		}
+	}
+	return nil
+}
+
+func (x *Operation) GetCond() *Cond {
+	if x != nil {
+		if x, ok := x.Body.(*Operation_Cond); ok {
+			return x.Cond
+		}
+	}
+	return nil
+}
+
+type isOperation_Body interface {
+	isOperation_Body()
+}
+
+type Operation_Calc struct {
+	Calc *Calc `protobuf:"bytes,3,opt,name=calc,proto3,oneof"`
+}
+
+type Operation_Print struct {
+	Print *Print `protobuf:"bytes,4,opt,name=print,proto3,oneof"`
+}
+
+type Operation_Expr struct {
+	Expr *Expr `protobuf:"bytes,5,opt,name=expr,proto3,oneof"`
+}
+
+type Operation_Cond struct {
+	Cond *Cond `protobuf:"bytes,6,opt,name=cond,proto3,oneof"`
+}
+
+func (*Operation_Calc) isOperation_Body() {}
+
+func (*Operation_Print) isOperation_Body() {}
+
+func (*Operation_Expr) isOperation_Body() {}
+
+func (*Operation_Cond) isOperation_Body() {}
+
+type Options struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	PrintOrder PrintOrder             `protobuf:"varint,1,opt,name=print_order,json=printOrder,proto3,enum=calculator.v2.PrintOrder" json:"print_order,omitempty"`
+	Numbers    NumberMode             `protobuf:"varint,2,opt,name=numbers,proto3,enum=calculator.v2.NumberMode" json:"numbers,omitempty"`
+	Overflow   OverflowMode           `protobuf:"varint,3,opt,name=overflow,proto3,enum=calculator.v2.OverflowMode" json:"overflow,omitempty"`
+	// Number of fractional digits kept in decimal results, 2 if unset.
+	DecimalScale    *int32         `protobuf:"varint,4,opt,name=decimal_scale,json=decimalScale,proto3,oneof" json:"decimal_scale,omitempty"`
+	DecimalRounding RoundingMode   `protobuf:"varint,5,opt,name=decimal_rounding,json=decimalRounding,proto3,enum=calculator.v2.RoundingMode" json:"decimal_rounding,omitempty"`
+	Evaluation      EvaluationMode `protobuf:"varint,6,opt,name=evaluation,proto3,enum=calculator.v2.EvaluationMode" json:"evaluation,omitempty"`
+	Errors          ErrorMode      `protobuf:"varint,7,opt,name=errors,proto3,enum=calculator.v2.ErrorMode" json:"errors,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Options) Reset() {
+	*x = Options{}
+	mi := &file_v2_calculator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Options) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Options) ProtoMessage() {}
+
+func (x *Options) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_calculator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Options.ProtoReflect.Descriptor instead.
+func (*Options) Descriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Options) GetPrintOrder() PrintOrder {
+	if x != nil {
+		return x.PrintOrder
+	}
+	return PrintOrder_PRINT_ORDER_UNSPECIFIED
+}
+
+func (x *Options) GetNumbers() NumberMode {
+	if x != nil {
+		return x.Numbers
+	}
+	return NumberMode_NUMBER_MODE_UNSPECIFIED
+}
+
+func (x *Options) GetOverflow() OverflowMode {
+	if x != nil {
+		return x.Overflow
+	}
+	return OverflowMode_OVERFLOW_MODE_UNSPECIFIED
+}
+
+func (x *Options) GetDecimalScale() int32 {
+	if x != nil && x.DecimalScale != nil {
+		return *x.DecimalScale
+	}
+	return 0
+}
+
+func (x *Options) GetDecimalRounding() RoundingMode {
+	if x != nil {
+		return x.DecimalRounding
+	}
+	return RoundingMode_ROUNDING_MODE_UNSPECIFIED
+}
+
+func (x *Options) GetEvaluation() EvaluationMode {
+	if x != nil {
+		return x.Evaluation
+	}
+	return EvaluationMode_EVALUATION_MODE_UNSPECIFIED
+}
+
+func (x *Options) GetErrors() ErrorMode {
+	if x != nil {
+		return x.Errors
+	}
+	return ErrorMode_ERROR_MODE_UNSPECIFIED
+}
+
+type Request struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Source:
+	//
+	//	*Request_Operations
+	//	*Request_Program
+	Source  isRequest_Source `protobuf_oneof:"source"`
+	Options *Options         `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	// Values assigned to variables before the operations run, typed according
+	// to the numbers mode like operand literals. Operations read them like any
+	// variable but cannot assign them.
+	Inputs        map[string]*Value `protobuf:"bytes,4,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Request) Reset() {
+	*x = Request{}
+	mi := &file_v2_calculator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Request) ProtoMessage() {}
+
+func (x *Request) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_calculator_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Request.ProtoReflect.Descriptor instead.
+func (*Request) Descriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Request) GetSource() isRequest_Source {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *Request) GetOperations() *Operations {
+	if x != nil {
+		if x, ok := x.Source.(*Request_Operations); ok {
+			return x.Operations
+		}
+	}
+	return nil
+}
+
+func (x *Request) GetProgram() string {
+	if x != nil {
+		if x, ok := x.Source.(*Request_Program); ok {
+			return x.Program
+		}
+	}
+	return ""
+}
+
+func (x *Request) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *Request) GetInputs() map[string]*Value {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+type isRequest_Source interface {
+	isRequest_Source()
+}
+
+type Request_Operations struct {
+	Operations *Operations `protobuf:"bytes,1,opt,name=operations,proto3,oneof"`
+}
+
+type Request_Program struct {
+	// Program in the line-based text format ("x = 3 + 8", "print x", "#"
+	// comments).
+	Program string `protobuf:"bytes,2,opt,name=program,proto3,oneof"`
+}
+
+func (*Request_Operations) isRequest_Source() {}
+
+func (*Request_Program) isRequest_Source() {}
+
+type Operations struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Operation     []*Operation           `protobuf:"bytes,1,rep,name=operation,proto3" json:"operation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Operations) Reset() {
+	*x = Operations{}
+	mi := &file_v2_calculator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Operations) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operations) ProtoMessage() {}
+
+func (x *Operations) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_calculator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operations.ProtoReflect.Descriptor instead.
+func (*Operations) Descriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Operations) GetOperation() []*Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return nil
+}
+
+type Value struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*Value_Number
+	//	*Value_BigNumber
+	//	*Value_FloatNumber
+	//	*Value_DecimalNumber
+	Value         isValue_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Value) Reset() {
+	*x = Value{}
+	mi := &file_v2_calculator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Value) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Value) ProtoMessage() {}
+
+func (x *Value) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_calculator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Value.ProtoReflect.Descriptor instead.
+func (*Value) Descriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Value) GetValue() isValue_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Value) GetNumber() int64 {
+	if x != nil {
+		if x, ok := x.Value.(*Value_Number); ok {
+			return x.Number
+		}
+	}
+	return 0
+}
+
+func (x *Value) GetBigNumber() string {
+	if x != nil {
+		if x, ok := x.Value.(*Value_BigNumber); ok {
+			return x.BigNumber
+		}
+	}
+	return ""
+}
+
+func (x *Value) GetFloatNumber() float64 {
+	if x != nil {
+		if x, ok := x.Value.(*Value_FloatNumber); ok {
+			return x.FloatNumber
+		}
+	}
+	return 0
+}
+
+func (x *Value) GetDecimalNumber() string {
+	if x != nil {
+		if x, ok := x.Value.(*Value_DecimalNumber); ok {
+			return x.DecimalNumber
+		}
+	}
+	return ""
+}
+
+type isValue_Value interface {
+	isValue_Value()
+}
+
+type Value_Number struct {
+	Number int64 `protobuf:"varint,1,opt,name=number,proto3,oneof"`
+}
+
+type Value_BigNumber struct {
+	// Set in the big numbers mode.
+	BigNumber string `protobuf:"bytes,2,opt,name=big_number,json=bigNumber,proto3,oneof"`
+}
+
+type Value_FloatNumber struct {
+	FloatNumber float64 `protobuf:"fixed64,3,opt,name=float_number,json=floatNumber,proto3,oneof"`
+}
+
+type Value_DecimalNumber struct {
+	DecimalNumber string `protobuf:"bytes,4,opt,name=decimal_number,json=decimalNumber,proto3,oneof"`
+}
+
+func (*Value_Number) isValue_Value() {}
+
+func (*Value_BigNumber) isValue_Value() {}
+
+func (*Value_FloatNumber) isValue_Value() {}
+
+func (*Value_DecimalNumber) isValue_Value() {}
+
+type Variable struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Var   string                 `protobuf:"bytes,1,opt,name=var,proto3" json:"var,omitempty"`
+	Value *Value                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// Sequence number of the print, set only for the completion print order.
+	Seq           *int64 `protobuf:"varint,3,opt,name=seq,proto3,oneof" json:"seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Variable) Reset() {
+	*x = Variable{}
+	mi := &file_v2_calculator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Variable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Variable) ProtoMessage() {}
+
+func (x *Variable) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_calculator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Variable.ProtoReflect.Descriptor instead.
+func (*Variable) Descriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Variable) GetVar() string {
+	if x != nil {
+		return x.Var
+	}
+	return ""
+}
+
+func (x *Variable) GetValue() *Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Variable) GetSeq() int64 {
+	if x != nil && x.Seq != nil {
+		return *x.Seq
+	}
+	return 0
+}
+
+// Summary of the assignments of a program. Intermediate results of
+// expressions are not counted.
+type Summary struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Computed int64                  `protobuf:"varint,1,opt,name=computed,proto3" json:"computed,omitempty"`
+	// Variables that were not computed, as in the lazy evaluation mode.
+	Skipped       []string `protobuf:"bytes,2,rep,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Summary) Reset() {
+	*x = Summary{}
+	mi := &file_v2_calculator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Summary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Summary) ProtoMessage() {}
+
+func (x *Summary) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_calculator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Summary.ProtoReflect.Descriptor instead.
+func (*Summary) Descriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Summary) GetComputed() int64 {
+	if x != nil {
+		return x.Computed
+	}
+	return 0
+}
+
+func (x *Summary) GetSkipped() []string {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+// Failure of an operation, reported in the collect errors mode.
+type OperationError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the operation in the request.
+	Index         int32     `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Var           string    `protobuf:"bytes,2,opt,name=var,proto3" json:"var,omitempty"`
+	Code          ErrorCode `protobuf:"varint,3,opt,name=code,proto3,enum=calculator.v2.ErrorCode" json:"code,omitempty"`
+	Message       string    `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OperationError) Reset() {
+	*x = OperationError{}
+	mi := &file_v2_calculator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperationError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationError) ProtoMessage() {}
+
+func (x *OperationError) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_calculator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationError.ProtoReflect.Descriptor instead.
+func (*OperationError) Descriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *OperationError) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *OperationError) GetVar() string {
+	if x != nil {
+		return x.Var
+	}
+	return ""
+}
+
+func (x *OperationError) GetCode() ErrorCode {
+	if x != nil {
+		return x.Code
+	}
+	return ErrorCode_ERROR_CODE_UNSPECIFIED
+}
+
+func (x *OperationError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type Metadata struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Identifier of the request in the server logs.
+	RequestId string `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Time spent executing the program.
+	Elapsed       *durationpb.Duration `protobuf:"bytes,2,opt,name=elapsed,proto3" json:"elapsed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Metadata) Reset() {
+	*x = Metadata{}
+	mi := &file_v2_calculator_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Metadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metadata) ProtoMessage() {}
+
+func (x *Metadata) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_calculator_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
+func (*Metadata) Descriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Metadata) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *Metadata) GetElapsed() *durationpb.Duration {
+	if x != nil {
+		return x.Elapsed
+	}
+	return nil
+}
+
+type Response struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*Variable            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Summary       *Summary               `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	Errors        []*OperationError      `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	Metadata      *Metadata              `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Response) Reset() {
+	*x = Response{}
+	mi := &file_v2_calculator_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Response) ProtoMessage() {}
+
+func (x *Response) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_calculator_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Response.ProtoReflect.Descriptor instead.
+func (*Response) Descriptor() ([]byte, []int) {
+	return file_v2_calculator_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Response) GetItems() []*Variable {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Response) GetSummary() *Summary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *Response) GetErrors() []*OperationError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *Response) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+var File_v2_calculator_proto protoreflect.FileDescriptor
+
+const file_v2_calculator_proto_rawDesc = "" +
+	"\n" +
+	"\x13v2/calculator.proto\x12\rcalculator.v2\x1a\x1egoogle/protobuf/duration.proto\"\xb9\x01\n" +
+	"\aOperand\x12\x18\n" +
+	"\x06number\x18\x01 \x01(\x03H\x00R\x06number\x12\x1c\n" +
+	"\bvariable\x18\x02 \x01(\tH\x00R\bvariable\x12\x1f\n" +
+	"\n" +
+	"big_number\x18\x03 \x01(\tH\x00R\tbigNumber\x12#\n" +
+	"\ffloat_number\x18\x04 \x01(\x01H\x00R\vfloatNumber\x12'\n" +
+	"\x0edecimal_number\x18\x05 \x01(\tH\x00R\rdecimalNumberB\a\n" +
+	"\x05value\"[\n" +
+	"\x04Calc\x12'\n" +
+	"\x02op\x18\x01 \x01(\x0e2\x17.calculator.v2.OperatorR\x02op\x12*\n" +
+	"\x04args\x18\x02 \x03(\v2\x16.calculator.v2.OperandR\x04args\"\a\n" +
+	"\x05Print\"\x1a\n" +
+	"\x04Expr\x12\x12\n" +
+	"\x04expr\x18\x01 \x01(\tR\x04expr\"\x8a\x01\n" +
+	"\x04Cond\x12*\n" +
+	"\x04cond\x18\x01 \x01(\v2\x16.calculator.v2.OperandR\x04cond\x12*\n" +
+	"\x04then\x18\x02 \x01(\v2\x16.calculator.v2.OperandR\x04then\x12*\n" +
+	"\x04else\x18\x03 \x01(\v2\x16.calculator.v2.OperandR\x04else\"\x86\x02\n" +
+	"\tOperation\x120\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1c.calculator.v2.OperationTypeR\x04type\x12\x10\n" +
+	"\x03var\x18\x02 \x01(\tR\x03var\x12)\n" +
+	"\x04calc\x18\x03 \x01(\v2\x13.calculator.v2.CalcH\x00R\x04calc\x12,\n" +
+	"\x05print\x18\x04 \x01(\v2\x14.calculator.v2.PrintH\x00R\x05print\x12)\n" +
+	"\x04expr\x18\x05 \x01(\v2\x13.calculator.v2.ExprH\x00R\x04expr\x12)\n" +
+	"\x04cond\x18\x06 \x01(\v2\x13.calculator.v2.CondH\x00R\x04condB\x06\n" +
+	"\x04body\"\xa8\x03\n" +
+	"\aOptions\x12:\n" +
+	"\vprint_order\x18\x01 \x01(\x0e2\x19.calculator.v2.PrintOrderR\n" +
+	"printOrder\x123\n" +
+	"\anumbers\x18\x02 \x01(\x0e2\x19.calculator.v2.NumberModeR\anumbers\x127\n" +
+	"\boverflow\x18\x03 \x01(\x0e2\x1b.calculator.v2.OverflowModeR\boverflow\x12(\n" +
+	"\rdecimal_scale\x18\x04 \x01(\x05H\x00R\fdecimalScale\x88\x01\x01\x12F\n" +
+	"\x10decimal_rounding\x18\x05 \x01(\x0e2\x1b.calculator.v2.RoundingModeR\x0fdecimalRounding\x12=\n" +
+	"\n" +
+	"evaluation\x18\x06 \x01(\x0e2\x1d.calculator.v2.EvaluationModeR\n" +
+	"evaluation\x120\n" +
+	"\x06errors\x18\a \x01(\x0e2\x18.calculator.v2.ErrorModeR\x06errorsB\x10\n" +
+	"\x0e_decimal_scale\"\xab\x02\n" +
+	"\aRequest\x12;\n" +
+	"\n" +
+	"operations\x18\x01 \x01(\v2\x19.calculator.v2.OperationsH\x00R\n" +
+	"operations\x12\x1a\n" +
+	"\aprogram\x18\x02 \x01(\tH\x00R\aprogram\x120\n" +
+	"\aoptions\x18\x03 \x01(\v2\x16.calculator.v2.OptionsR\aoptions\x12:\n" +
+	"\x06inputs\x18\x04 \x03(\v2\".calculator.v2.Request.InputsEntryR\x06inputs\x1aO\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12*\n" +
+	"\x05value\x18\x02 \x01(\v2\x14.calculator.v2.ValueR\x05value:\x028\x01B\b\n" +
+	"\x06source\"D\n" +
+	"\n" +
+	"Operations\x126\n" +
+	"\toperation\x18\x01 \x03(\v2\x18.calculator.v2.OperationR\toperation\"\x99\x01\n" +
+	"\x05Value\x12\x18\n" +
+	"\x06number\x18\x01 \x01(\x03H\x00R\x06number\x12\x1f\n" +
+	"\n" +
+	"big_number\x18\x02 \x01(\tH\x00R\tbigNumber\x12#\n" +
+	"\ffloat_number\x18\x03 \x01(\x01H\x00R\vfloatNumber\x12'\n" +
+	"\x0edecimal_number\x18\x04 \x01(\tH\x00R\rdecimalNumberB\a\n" +
+	"\x05value\"g\n" +
+	"\bVariable\x12\x10\n" +
+	"\x03var\x18\x01 \x01(\tR\x03var\x12*\n" +
+	"\x05value\x18\x02 \x01(\v2\x14.calculator.v2.ValueR\x05value\x12\x15\n" +
+	"\x03seq\x18\x03 \x01(\x03H\x00R\x03seq\x88\x01\x01B\x06\n" +
+	"\x04_seq\"?\n" +
+	"\aSummary\x12\x1a\n" +
+	"\bcomputed\x18\x01 \x01(\x03R\bcomputed\x12\x18\n" +
+	"\askipped\x18\x02 \x03(\tR\askipped\"\x80\x01\n" +
+	"\x0eOperationError\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x10\n" +
+	"\x03var\x18\x02 \x01(\tR\x03var\x12,\n" +
+	"\x04code\x18\x03 \x01(\x0e2\x18.calculator.v2.ErrorCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"^\n" +
+	"\bMetadata\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x123\n" +
+	"\aelapsed\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\aelapsed\"\xd7\x01\n" +
+	"\bResponse\x12-\n" +
+	"\x05items\x18\x01 \x03(\v2\x17.calculator.v2.VariableR\x05items\x120\n" +
+	"\asummary\x18\x02 \x01(\v2\x16.calculator.v2.SummaryR\asummary\x125\n" +
+	"\x06errors\x18\x03 \x03(\v2\x1d.calculator.v2.OperationErrorR\x06errors\x123\n" +
+	"\bmetadata\x18\x04 \x01(\v2\x17.calculator.v2.MetadataR\bmetadata*\x94\x01\n" +
+	"\rOperationType\x12\x1e\n" +
+	"\x1aOPERATION_TYPE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13OPERATION_TYPE_CALC\x10\x01\x12\x18\n" +
+	"\x14OPERATION_TYPE_PRINT\x10\x02\x12\x17\n" +
+	"\x13OPERATION_TYPE_EXPR\x10\x03\x12\x17\n" +
+	"\x13OPERATION_TYPE_COND\x10\x04*\x92\x04\n" +
+	"\bOperator\x12\x18\n" +
+	"\x14OPERATOR_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fOPERATOR_ADD\x10\x01\x12\x10\n" +
+	"\fOPERATOR_SUB\x10\x02\x12\x10\n" +
+	"\fOPERATOR_MUL\x10\x03\x12\x10\n" +
+	"\fOPERATOR_DIV\x10\x04\x12\x10\n" +
+	"\fOPERATOR_MOD\x10\x05\x12\x10\n" +
+	"\fOPERATOR_POW\x10\x06\x12\x10\n" +
+	"\fOPERATOR_AND\x10\a\x12\x0f\n" +
+	"\vOPERATOR_OR\x10\b\x12\x10\n" +
+	"\fOPERATOR_XOR\x10\t\x12\x10\n" +
+	"\fOPERATOR_SHL\x10\n" +
+	"\x12\x10\n" +
+	"\fOPERATOR_SHR\x10\v\x12\x0f\n" +
+	"\vOPERATOR_EQ\x10\f\x12\x0f\n" +
+	"\vOPERATOR_NE\x10\r\x12\x0f\n" +
+	"\vOPERATOR_LT\x10\x0e\x12\x0f\n" +
+	"\vOPERATOR_LE\x10\x0f\x12\x0f\n" +
+	"\vOPERATOR_GT\x10\x10\x12\x0f\n" +
+	"\vOPERATOR_GE\x10\x11\x12\x10\n" +
+	"\fOPERATOR_MIN\x10\x12\x12\x10\n" +
+	"\fOPERATOR_MAX\x10\x13\x12\x15\n" +
+	"\x11OPERATOR_FLOORDIV\x10\x14\x12\x14\n" +
+	"\x10OPERATOR_CEILDIV\x10\x15\x12\x10\n" +
+	"\fOPERATOR_SUM\x10\x16\x12\x14\n" +
+	"\x10OPERATOR_PRODUCT\x10\x17\x12\x10\n" +
+	"\fOPERATOR_NEG\x10\x18\x12\x10\n" +
+	"\fOPERATOR_ABS\x10\x19\x12\x11\n" +
+	"\rOPERATOR_SIGN\x10\x1a\x12\x11\n" +
+	"\rOPERATOR_SQRT\x10\x1b*^\n" +
+	"\n" +
+	"PrintOrder\x12\x1b\n" +
+	"\x17PRINT_ORDER_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13PRINT_ORDER_REQUEST\x10\x01\x12\x1a\n" +
+	"\x16PRINT_ORDER_COMPLETION\x10\x02*n\n" +
+	"\n" +
+	"NumberMode\x12\x1b\n" +
+	"\x17NUMBER_MODE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11NUMBER_MODE_INT64\x10\x01\x12\x13\n" +
+	"\x0fNUMBER_MODE_BIG\x10\x02\x12\x17\n" +
+	"\x13NUMBER_MODE_DECIMAL\x10\x03*\x82\x01\n" +
+	"\fOverflowMode\x12\x1d\n" +
+	"\x19OVERFLOW_MODE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16OVERFLOW_MODE_WRAPPING\x10\x01\x12\x19\n" +
+	"\x15OVERFLOW_MODE_CHECKED\x10\x02\x12\x1c\n" +
+	"\x18OVERFLOW_MODE_SATURATING\x10\x03*\xc7\x01\n" +
+	"\fRoundingMode\x12\x1d\n" +
+	"\x19ROUNDING_MODE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15ROUNDING_MODE_HALF_UP\x10\x01\x12\x1b\n" +
+	"\x17ROUNDING_MODE_HALF_EVEN\x10\x02\x12\x16\n" +
+	"\x12ROUNDING_MODE_DOWN\x10\x03\x12\x14\n" +
+	"\x10ROUNDING_MODE_UP\x10\x04\x12\x17\n" +
+	"\x13ROUNDING_MODE_FLOOR\x10\x05\x12\x19\n" +
+	"\x15ROUNDING_MODE_CEILING\x10\x06*f\n" +
+	"\x0eEvaluationMode\x12\x1f\n" +
+	"\x1bEVALUATION_MODE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15EVALUATION_MODE_EAGER\x10\x01\x12\x18\n" +
+	"\x14EVALUATION_MODE_LAZY\x10\x02*Y\n" +
+	"\tErrorMode\x12\x1a\n" +
+	"\x16ERROR_MODE_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14ERROR_MODE_FAIL_FAST\x10\x01\x12\x16\n" +
+	"\x12ERROR_MODE_COLLECT\x10\x02*\x83\x04\n" +
+	"\tErrorCode\x12\x1a\n" +
+	"\x16ERROR_CODE_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aERROR_CODE_INVALID_REQUEST\x10\x01\x12 \n" +
+	"\x1cERROR_CODE_INVALID_OPERATION\x10\x02\x12\x1e\n" +
+	"\x1aERROR_CODE_INVALID_OPERAND\x10\x03\x12\x1b\n" +
+	"\x17ERROR_CODE_SYNTAX_ERROR\x10\x04\x12!\n" +
+	"\x1dERROR_CODE_UNDEFINED_VARIABLE\x10\x05\x12#\n" +
+	"\x1fERROR_CODE_DUPLICATE_ASSIGNMENT\x10\x06\x12\x14\n" +
+	"\x10ERROR_CODE_CYCLE\x10\a\x12\x1f\n" +
+	"\x1bERROR_CODE_DIVISION_BY_ZERO\x10\b\x12\x17\n" +
+	"\x13ERROR_CODE_OVERFLOW\x10\t\x12\x1e\n" +
+	"\x1aERROR_CODE_UPSTREAM_FAILED\x10\n" +
+	"\x12\x1f\n" +
+	"\x1bERROR_CODE_EVALUATION_ERROR\x10\v\x12\x16\n" +
+	"\x12ERROR_CODE_TIMEOUT\x10\f\x12\x18\n" +
+	"\x14ERROR_CODE_CANCELLED\x10\r\x12\x17\n" +
+	"\x13ERROR_CODE_INTERNAL\x10\x0e\x12\x18\n" +
+	"\x14ERROR_CODE_NOT_FOUND\x10\x0f\x12\x1d\n" +
+	"\x19ERROR_CODE_LIMIT_EXCEEDED\x10\x102H\n" +
+	"\n" +
+	"Calculator\x12:\n" +
+	"\aExecute\x12\x16.calculator.v2.Request\x1a\x17.calculator.v2.ResponseB\"Z upgraded-calculator/gen/v2;genv2b\x06proto3"
+
+var (
+	file_v2_calculator_proto_rawDescOnce sync.Once
+	file_v2_calculator_proto_rawDescData []byte
+)
+
+func file_v2_calculator_proto_rawDescGZIP() []byte {
+	file_v2_calculator_proto_rawDescOnce.Do(func() {
+		file_v2_calculator_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_v2_calculator_proto_rawDesc), len(file_v2_calculator_proto_rawDesc)))
+	})
+	return file_v2_calculator_proto_rawDescData
+}
+
+var file_v2_calculator_proto_enumTypes = make([]protoimpl.EnumInfo, 9)
+var file_v2_calculator_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_v2_calculator_proto_goTypes = []any{
+	(OperationType)(0),          // 0: calculator.v2.OperationType
+	(Operator)(0),               // 1: calculator.v2.Operator
+	(PrintOrder)(0),             // 2: calculator.v2.PrintOrder
+	(NumberMode)(0),             // 3: calculator.v2.NumberMode
+	(OverflowMode)(0),           // 4: calculator.v2.OverflowMode
+	(RoundingMode)(0),           // 5: calculator.v2.RoundingMode
+	(EvaluationMode)(0),         // 6: calculator.v2.EvaluationMode
+	(ErrorMode)(0),              // 7: calculator.v2.ErrorMode
+	(ErrorCode)(0),              // 8: calculator.v2.ErrorCode
+	(*Operand)(nil),             // 9: calculator.v2.Operand
+	(*Calc)(nil),                // 10: calculator.v2.Calc
+	(*Print)(nil),               // 11: calculator.v2.Print
+	(*Expr)(nil),                // 12: calculator.v2.Expr
+	(*Cond)(nil),                // 13: calculator.v2.Cond
+	(*Operation)(nil),           // 14: calculator.v2.Operation
+	(*Options)(nil),             // 15: calculator.v2.Options
+	(*Request)(nil),             // 16: calculator.v2.Request
+	(*Operations)(nil),          // 17: calculator.v2.Operations
+	(*Value)(nil),               // 18: calculator.v2.Value
+	(*Variable)(nil),            // 19: calculator.v2.Variable
+	(*Summary)(nil),             // 20: calculator.v2.Summary
+	(*OperationError)(nil),      // 21: calculator.v2.OperationError
+	(*Metadata)(nil),            // 22: calculator.v2.Metadata
+	(*Response)(nil),            // 23: calculator.v2.Response
+	nil,                         // 24: calculator.v2.Request.InputsEntry
+	(*durationpb.Duration)(nil), // 25: google.protobuf.Duration
+}
+var file_v2_calculator_proto_depIdxs = []int32{
+	1,  // 0: calculator.v2.Calc.op:type_name -> calculator.v2.Operator
+	9,  // 1: calculator.v2.Calc.args:type_name -> calculator.v2.Operand
+	9,  // 2: calculator.v2.Cond.cond:type_name -> calculator.v2.Operand
+	9,  // 3: calculator.v2.Cond.then:type_name -> calculator.v2.Operand
+	9,  // 4: calculator.v2.Cond.else:type_name -> calculator.v2.Operand
+	0,  // 5: calculator.v2.Operation.type:type_name -> calculator.v2.OperationType
+	10, // 6: calculator.v2.Operation.calc:type_name -> calculator.v2.Calc
+	11, // 7: calculator.v2.Operation.print:type_name -> calculator.v2.Print
+	12, // 8: calculator.v2.Operation.expr:type_name -> calculator.v2.Expr
+	13, // 9: calculator.v2.Operation.cond:type_name -> calculator.v2.Cond
+	2,  // 10: calculator.v2.Options.print_order:type_name -> calculator.v2.PrintOrder
+	3,  // 11: calculator.v2.Options.numbers:type_name -> calculator.v2.NumberMode
+	4,  // 12: calculator.v2.Options.overflow:type_name -> calculator.v2.OverflowMode
+	5,  // 13: calculator.v2.Options.decimal_rounding:type_name -> calculator.v2.RoundingMode
+	6,  // 14: calculator.v2.Options.evaluation:type_name -> calculator.v2.EvaluationMode
+	7,  // 15: calculator.v2.Options.errors:type_name -> calculator.v2.ErrorMode
+	17, // 16: calculator.v2.Request.operations:type_name -> calculator.v2.Operations
+	15, // 17: calculator.v2.Request.options:type_name -> calculator.v2.Options
+	24, // 18: calculator.v2.Request.inputs:type_name -> calculator.v2.Request.InputsEntry
+	14, // 19: calculator.v2.Operations.operation:type_name -> calculator.v2.Operation
+	18, // 20: calculator.v2.Variable.value:type_name -> calculator.v2.Value
+	8,  // 21: calculator.v2.OperationError.code:type_name -> calculator.v2.ErrorCode
+	25, // 22: calculator.v2.Metadata.elapsed:type_name -> google.protobuf.Duration
+	19, // 23: calculator.v2.Response.items:type_name -> calculator.v2.Variable
+	20, // 24: calculator.v2.Response.summary:type_name -> calculator.v2.Summary
+	21, // 25: calculator.v2.Response.errors:type_name -> calculator.v2.OperationError
+	22, // 26: calculator.v2.Response.metadata:type_name -> calculator.v2.Metadata
+	18, // 27: calculator.v2.Request.InputsEntry.value:type_name -> calculator.v2.Value
+	16, // 28: calculator.v2.Calculator.Execute:input_type -> calculator.v2.Request
+	23, // 29: calculator.v2.Calculator.Execute:output_type -> calculator.v2.Response
+	29, // [29:30] is the sub-list for method output_type
+	28, // [28:29] is the sub-list for method input_type
+	28, // [28:28] is the sub-list for extension type_name
+	28, // [28:28] is the sub-list for extension extendee
+	0,  // [0:28] is the sub-list for field type_name
+}
+
+func init() { file_v2_calculator_proto_init() }
+func file_v2_calculator_proto_init() {
+	if File_v2_calculator_proto != nil {
+		return
+	}
+	file_v2_calculator_proto_msgTypes[0].OneofWrappers = []any{
+		(*Operand_Number)(nil),
+		(*Operand_Variable)(nil),
+		(*Operand_BigNumber)(nil),
+		(*Operand_FloatNumber)(nil),
+		(*Operand_DecimalNumber)(nil),
+	}
+	file_v2_calculator_proto_msgTypes[5].OneofWrappers = []any{
+		(*Operation_Calc)(nil),
+		(*Operation_Print)(nil),
+		(*Operation_Expr)(nil),
+		(*Operation_Cond)(nil),
+	}
+	file_v2_calculator_proto_msgTypes[6].OneofWrappers = []any{}
+	file_v2_calculator_proto_msgTypes[7].OneofWrappers = []any{
+		(*Request_Operations)(nil),
+		(*Request_Program)(nil),
+	}
+	file_v2_calculator_proto_msgTypes[9].OneofWrappers = []any{
+		(*Value_Number)(nil),
+		(*Value_BigNumber)(nil),
+		(*Value_FloatNumber)(nil),
+		(*Value_DecimalNumber)(nil),
+	}
+	file_v2_calculator_proto_msgTypes[10].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_v2_calculator_proto_rawDesc), len(file_v2_calculator_proto_rawDesc)),
+			NumEnums:      9,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_v2_calculator_proto_goTypes,
+		DependencyIndexes: file_v2_calculator_proto_depIdxs,
+		EnumInfos:         file_v2_calculator_proto_enumTypes,
+		MessageInfos:      file_v2_calculator_proto_msgTypes,
+	}.Build()
+	File_v2_calculator_proto = out.File
+	file_v2_calculator_proto_goTypes = nil
+	file_v2_calculator_proto_depIdxs = nil
+}
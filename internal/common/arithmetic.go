@@ -0,0 +1,740 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+type OverflowMode string
+
+const (
+	// WrappingOverflow silently wraps around int64 bounds. It is the default mode.
+	WrappingOverflow OverflowMode = "wrapping"
+	// CheckedOverflow fails the operation with an *OverflowError.
+	CheckedOverflow OverflowMode = "checked"
+	// SaturatingOverflow clamps the result to math.MaxInt64 or math.MinInt64.
+	SaturatingOverflow OverflowMode = "saturating"
+)
+
+func (mode *OverflowMode) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	switch OverflowMode(s) {
+	case "", WrappingOverflow, CheckedOverflow, SaturatingOverflow:
+		*mode = OverflowMode(s)
+		return nil
+	default:
+		return NewError(InvalidRequestCode, "invalid overflow mode").WithField("overflow")
+	}
+}
+
+// OverflowError is returned in the checked overflow mode when the result of
+// an operation does not fit into int64.
+type OverflowError struct {
+	Var      string
+	Operands []int64
+	Op       CalcAvailableOperation
+	// Underflow is set when the exact result is below math.MinInt64.
+	Underflow bool
+}
+
+func (e *OverflowError) ErrorCode() ErrorCode {
+	return OverflowCode
+}
+
+func (e *OverflowError) Error() string {
+	kind := "overflow"
+	if e.Underflow {
+		kind = "underflow"
+	}
+	if len(e.Operands) == 2 {
+		return fmt.Sprintf("integer %s computing variable '%s': %d %s %d", kind, e.Var, e.Operands[0], e.Op, e.Operands[1])
+	}
+	operands := make([]string, len(e.Operands))
+	for i, operand := range e.Operands {
+		operands[i] = strconv.FormatInt(operand, 10)
+	}
+	return fmt.Sprintf("integer %s computing variable '%s': %s(%s)", kind, e.Var, e.Op, strings.Join(operands, ", "))
+}
+
+// handleOverflow applies the overflow mode to a wrapped int64 result.
+func handleOverflow(ctx EvalContext, symbol CalcAvailableOperation, operands []int64, wrapped int64, direction int) (int64, error) {
+	if direction == 0 {
+		return wrapped, nil
+	}
+	switch ctx.Options.Overflow {
+	case CheckedOverflow:
+		return 0, &OverflowError{Var: ctx.Var, Operands: operands, Op: symbol, Underflow: direction < 0}
+	case SaturatingOverflow:
+		if direction < 0 {
+			return math.MinInt64, nil
+		}
+		return math.MaxInt64, nil
+	}
+	return wrapped, nil
+}
+
+// maxShift bounds left shifts and exponents of arbitrary-precision values so
+// that a single operation cannot exhaust memory.
+const maxShift = 1 << 20
+
+// maxBits bounds the size of arbitrary-precision results, decimals counting
+// their unscaled value, so that a chain of operations each within maxShift
+// cannot grow a value without limit either.
+const maxBits = 1 << 20
+
+var (
+	errDivisionByZero = NewError(DivisionByZeroCode, "division by zero")
+	errModuloByZero   = NewError(DivisionByZeroCode, "modulo by zero")
+	errNegativeExp    = NewError(InvalidOperandCode, "negative exponent")
+	errShiftRange     = NewError(InvalidOperandCode, "shift out of range")
+	errTooLarge       = NewError(OverflowCode, "result is too large")
+	errNegativeSqrt   = NewError(InvalidOperandCode, "square root of a negative number")
+)
+
+// numericFuncs are the per-kind implementations of a binary operator. A nil
+// function means the operator does not accept operands of that kind.
+type numericFuncs struct {
+	// Int returns the wrapped result together with the overflow direction:
+	// 1 when the exact result is above math.MaxInt64, -1 when it is below
+	// math.MinInt64 and 0 when it fits.
+	Int     func(left, right int64) (int64, int, error)
+	Big     func(left, right *big.Int) (*big.Int, error)
+	Decimal func(ctx EvalContext, left, right Decimal) (Decimal, error)
+	Float   func(left, right float64) (float64, error)
+}
+
+// unaryFuncs are the per-kind implementations of a unary operator, see numericFuncs.
+type unaryFuncs struct {
+	Int     func(v int64) (int64, int, error)
+	Big     func(v *big.Int) (*big.Int, error)
+	Decimal func(ctx EvalContext, v Decimal) (Decimal, error)
+	Float   func(v float64) (float64, error)
+}
+
+func kindsOf(implemented ...bool) []ValueKind {
+	var kinds []ValueKind
+	for kind, impl := range implemented {
+		if impl {
+			kinds = append(kinds, ValueKind(kind))
+		}
+	}
+	return kinds
+}
+
+// numericOperator builds a binary operator from per-kind implementations.
+// Operands are folded from left to right, so the same implementation also
+// serves variadic operators, which fold int64 operands exactly before the
+// overflow check. It handles int64 overflow according to the
+// overflow mode, rounds decimal results to the configured scale and rejects
+// non-finite float results as well as big and decimal results longer than
+// maxBits.
+func numericOperator(symbol CalcAvailableOperation, description string, funcs numericFuncs) Operator {
+	op := Operator{
+		Symbol:      symbol,
+		Arity:       2,
+		Description: description,
+		Kinds:       kindsOf(funcs.Int != nil, funcs.Big != nil, funcs.Decimal != nil, funcs.Float != nil),
+	}
+
+	op.Apply = func(ctx EvalContext, args []Value) (Value, error) {
+		switch args[0].Kind() {
+		case IntKind:
+			operands := make([]int64, len(args))
+			for i, arg := range args {
+				operands[i], _ = arg.Int64()
+			}
+			if len(args) > 2 {
+				return foldIntExact(ctx, symbol, funcs, operands)
+			}
+			acc := operands[0]
+			for _, next := range operands[1:] {
+				res, direction, err := funcs.Int(acc, next)
+				if err != nil {
+					return Value{}, err
+				}
+				if acc, err = handleOverflow(ctx, symbol, operands, res, direction); err != nil {
+					return Value{}, err
+				}
+			}
+			return NewIntValue(acc), nil
+		case BigKind:
+			acc := args[0].Big()
+			for _, next := range args[1:] {
+				res, err := funcs.Big(acc, next.Big())
+				if err != nil {
+					return Value{}, err
+				}
+				if res.BitLen() > maxBits {
+					return Value{}, errTooLarge
+				}
+				acc = res
+			}
+			return NewBigValue(acc), nil
+		case DecimalKind:
+			acc := args[0].Decimal()
+			for _, next := range args[1:] {
+				res, err := funcs.Decimal(ctx, acc, next.Decimal())
+				if err != nil {
+					return Value{}, err
+				}
+				if res.unscaled.BitLen() > maxBits {
+					return Value{}, errTooLarge
+				}
+				acc = res
+			}
+			return NewDecimalValue(acc.Rescale(ctx.DecimalScale(), ctx.Options.DecimalRounding)), nil
+		default:
+			acc := args[0].Float64()
+			for _, next := range args[1:] {
+				res, err := funcs.Float(acc, next.Float64())
+				if err != nil {
+					return Value{}, err
+				}
+				if math.IsInf(res, 0) || math.IsNaN(res) {
+					return Value{}, NewError(OverflowCode, "float result of %g %s %g is out of range", acc, symbol, next.Float64())
+				}
+				acc = res
+			}
+			return NewFloatValue(acc), nil
+		}
+	}
+	return op
+}
+
+// foldIntExact folds the int64 operands of a variadic operator with the
+// arbitrary-precision implementation, so that the overflow mode applies to
+// the final result only and intermediate overflows that cancel out later do
+// not change it.
+func foldIntExact(ctx EvalContext, symbol CalcAvailableOperation, funcs numericFuncs, operands []int64) (Value, error) {
+	acc := big.NewInt(operands[0])
+	for _, next := range operands[1:] {
+		res, err := funcs.Big(acc, big.NewInt(next))
+		if err != nil {
+			return Value{}, err
+		}
+		acc = res
+	}
+	if acc.IsInt64() {
+		return NewIntValue(acc.Int64()), nil
+	}
+	wrapped := int64(new(big.Int).And(acc, new(big.Int).SetUint64(math.MaxUint64)).Uint64())
+	res, err := handleOverflow(ctx, symbol, operands, wrapped, acc.Sign())
+	if err != nil {
+		return Value{}, err
+	}
+	return NewIntValue(res), nil
+}
+
+// unaryOperator builds a single operand operator, see numericOperator.
+func unaryOperator(symbol CalcAvailableOperation, description string, funcs unaryFuncs) Operator {
+	op := Operator{
+		Symbol:      symbol,
+		Arity:       1,
+		Description: description,
+		Kinds:       kindsOf(funcs.Int != nil, funcs.Big != nil, funcs.Decimal != nil, funcs.Float != nil),
+	}
+
+	op.Apply = func(ctx EvalContext, args []Value) (Value, error) {
+		arg := args[0]
+		switch arg.Kind() {
+		case IntKind:
+			v, _ := arg.Int64()
+			res, direction, err := funcs.Int(v)
+			if err != nil {
+				return Value{}, err
+			}
+			if res, err = handleOverflow(ctx, symbol, []int64{v}, res, direction); err != nil {
+				return Value{}, err
+			}
+			return NewIntValue(res), nil
+		case BigKind:
+			res, err := funcs.Big(arg.Big())
+			if err != nil {
+				return Value{}, err
+			}
+			if res.BitLen() > maxBits {
+				return Value{}, errTooLarge
+			}
+			return NewBigValue(res), nil
+		case DecimalKind:
+			res, err := funcs.Decimal(ctx, arg.Decimal())
+			if err != nil {
+				return Value{}, err
+			}
+			if res.unscaled.BitLen() > maxBits {
+				return Value{}, errTooLarge
+			}
+			return NewDecimalValue(res.Rescale(ctx.DecimalScale(), ctx.Options.DecimalRounding)), nil
+		default:
+			res, err := funcs.Float(arg.Float64())
+			if err != nil {
+				return Value{}, err
+			}
+			if math.IsInf(res, 0) || math.IsNaN(res) {
+				return Value{}, NewError(OverflowCode, "float result of %s(%g) is out of range", symbol, arg.Float64())
+			}
+			return NewFloatValue(res), nil
+		}
+	}
+	return op
+}
+
+// variadic lets the operator take any number of operands starting from minArity.
+func variadic(op Operator, minArity int) Operator {
+	op.Arity = minArity
+	op.Variadic = true
+	return op
+}
+
+func init() {
+	for _, op := range append(builtinOperators(), comparisonOperators()...) {
+		if err := RegisterOperator(op); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func builtinOperators() []Operator {
+	addFuncs := numericFuncs{
+		Int: func(left, right int64) (int64, int, error) {
+			res := left + right
+			if left > 0 && right > 0 && res < 0 {
+				return res, 1, nil
+			} else if left < 0 && right < 0 && res >= 0 {
+				return res, -1, nil
+			}
+			return res, 0, nil
+		},
+		Big: func(left, right *big.Int) (*big.Int, error) {
+			return new(big.Int).Add(left, right), nil
+		},
+		Decimal: func(_ EvalContext, left, right Decimal) (Decimal, error) {
+			return left.Add(right), nil
+		},
+		Float: func(left, right float64) (float64, error) {
+			return left + right, nil
+		},
+	}
+	mulFuncs := numericFuncs{
+		Int: func(left, right int64) (int64, int, error) {
+			res, direction := mulInt(left, right)
+			return res, direction, nil
+		},
+		Big: func(left, right *big.Int) (*big.Int, error) {
+			return new(big.Int).Mul(left, right), nil
+		},
+		Decimal: func(_ EvalContext, left, right Decimal) (Decimal, error) {
+			return left.Mul(right), nil
+		},
+		Float: func(left, right float64) (float64, error) {
+			return left * right, nil
+		},
+	}
+
+	return []Operator{
+		numericOperator(Add, "addition", addFuncs),
+		numericOperator(Sub, "subtraction", numericFuncs{
+			Int: func(left, right int64) (int64, int, error) {
+				res := left - right
+				if left >= 0 && right < 0 && res < 0 {
+					return res, 1, nil
+				} else if left < 0 && right > 0 && res >= 0 {
+					return res, -1, nil
+				}
+				return res, 0, nil
+			},
+			Big: func(left, right *big.Int) (*big.Int, error) {
+				return new(big.Int).Sub(left, right), nil
+			},
+			Decimal: func(_ EvalContext, left, right Decimal) (Decimal, error) {
+				return left.Sub(right), nil
+			},
+			Float: func(left, right float64) (float64, error) {
+				return left - right, nil
+			},
+		}),
+		numericOperator(Mul, "multiplication", mulFuncs),
+		divisionOperator(Div, "division, integer quotients are truncated towards zero", DownRounding),
+		divisionOperator(FloorDiv, "division rounding the quotient towards negative infinity", FloorRounding),
+		divisionOperator(CeilDiv, "division rounding the quotient towards positive infinity", CeilingRounding),
+		numericOperator(Mod, "remainder of the truncated division, it has the sign of the dividend", numericFuncs{
+			Int: func(left, right int64) (int64, int, error) {
+				if right == 0 {
+					return 0, 0, errModuloByZero
+				}
+				return left % right, 0, nil
+			},
+			Big: func(left, right *big.Int) (*big.Int, error) {
+				if right.Sign() == 0 {
+					return nil, errModuloByZero
+				}
+				return new(big.Int).Rem(left, right), nil
+			},
+			Decimal: func(_ EvalContext, left, right Decimal) (Decimal, error) {
+				if right.Sign() == 0 {
+					return Decimal{}, errModuloByZero
+				}
+				quo, _ := left.Quo(right, 0, DownRounding)
+				return left.Sub(quo.Mul(right)), nil
+			},
+			Float: func(left, right float64) (float64, error) {
+				if right == 0 {
+					return 0, errModuloByZero
+				}
+				return math.Mod(left, right), nil
+			},
+		}),
+		numericOperator(Pow, "power, the exponent must be a non-negative integer except for float operands", numericFuncs{
+			Int: powInt,
+			Big: func(left, right *big.Int) (*big.Int, error) {
+				if right.Sign() < 0 {
+					return nil, errNegativeExp
+				}
+				if left.CmpAbs(big.NewInt(1)) > 0 &&
+					(!right.IsInt64() || right.Int64() > maxShift/int64(left.BitLen())) {
+					return nil, errTooLarge
+				}
+				return new(big.Int).Exp(left, right, nil), nil
+			},
+			Decimal: powDecimal,
+			Float: func(left, right float64) (float64, error) {
+				return math.Pow(left, right), nil
+			},
+		}),
+		numericOperator(And, "bitwise AND of integers", numericFuncs{
+			Int: func(left, right int64) (int64, int, error) {
+				return left & right, 0, nil
+			},
+			Big: func(left, right *big.Int) (*big.Int, error) {
+				return new(big.Int).And(left, right), nil
+			},
+		}),
+		numericOperator(Or, "bitwise OR of integers", numericFuncs{
+			Int: func(left, right int64) (int64, int, error) {
+				return left | right, 0, nil
+			},
+			Big: func(left, right *big.Int) (*big.Int, error) {
+				return new(big.Int).Or(left, right), nil
+			},
+		}),
+		numericOperator(Xor, "bitwise XOR of integers", numericFuncs{
+			Int: func(left, right int64) (int64, int, error) {
+				return left ^ right, 0, nil
+			},
+			Big: func(left, right *big.Int) (*big.Int, error) {
+				return new(big.Int).Xor(left, right), nil
+			},
+		}),
+		numericOperator(Shl, "left shift of an integer, the count must be in [0, 63] for int64 values", numericFuncs{
+			Int: func(left, right int64) (int64, int, error) {
+				if right < 0 || right > 63 {
+					return 0, 0, errShiftRange
+				}
+				res := left << right
+				if res>>right != left {
+					if left < 0 {
+						return res, -1, nil
+					}
+					return res, 1, nil
+				}
+				return res, 0, nil
+			},
+			Big: func(left, right *big.Int) (*big.Int, error) {
+				if right.Sign() < 0 || !right.IsInt64() || right.Int64() > maxShift {
+					return nil, errShiftRange
+				}
+				return new(big.Int).Lsh(left, uint(right.Int64())), nil
+			},
+		}),
+		numericOperator(Shr, "arithmetic right shift of an integer, the count must be in [0, 63] for int64 values", numericFuncs{
+			Int: func(left, right int64) (int64, int, error) {
+				if right < 0 || right > 63 {
+					return 0, 0, errShiftRange
+				}
+				return left >> right, 0, nil
+			},
+			Big: func(left, right *big.Int) (*big.Int, error) {
+				if right.Sign() < 0 {
+					return nil, errShiftRange
+				}
+				if !right.IsInt64() || right.Int64() > int64(left.BitLen()) {
+					return new(big.Int).Rsh(left, uint(left.BitLen())), nil
+				}
+				return new(big.Int).Rsh(left, uint(right.Int64())), nil
+			},
+		}),
+		variadic(numericOperator(Sum, "sum of one or more operands", addFuncs), 1),
+		variadic(numericOperator(Product, "product of one or more operands", mulFuncs), 1),
+		unaryOperator(Neg, "negation", unaryFuncs{
+			Int: func(v int64) (int64, int, error) {
+				if v == math.MinInt64 {
+					return v, 1, nil
+				}
+				return -v, 0, nil
+			},
+			Big: func(v *big.Int) (*big.Int, error) {
+				return new(big.Int).Neg(v), nil
+			},
+			Decimal: func(_ EvalContext, v Decimal) (Decimal, error) {
+				return v.Neg(), nil
+			},
+			Float: func(v float64) (float64, error) {
+				return -v, nil
+			},
+		}),
+		unaryOperator(Abs, "absolute value", unaryFuncs{
+			Int: func(v int64) (int64, int, error) {
+				if v == math.MinInt64 {
+					return v, 1, nil
+				}
+				if v < 0 {
+					return -v, 0, nil
+				}
+				return v, 0, nil
+			},
+			Big: func(v *big.Int) (*big.Int, error) {
+				return new(big.Int).Abs(v), nil
+			},
+			Decimal: func(_ EvalContext, v Decimal) (Decimal, error) {
+				if v.Sign() < 0 {
+					return v.Neg(), nil
+				}
+				return v, nil
+			},
+			Float: func(v float64) (float64, error) {
+				return math.Abs(v), nil
+			},
+		}),
+		unaryOperator(Sign, "-1, 0 or 1 depending on the sign of the operand", unaryFuncs{
+			Int: func(v int64) (int64, int, error) {
+				switch {
+				case v < 0:
+					return -1, 0, nil
+				case v > 0:
+					return 1, 0, nil
+				}
+				return 0, 0, nil
+			},
+			Big: func(v *big.Int) (*big.Int, error) {
+				return big.NewInt(int64(v.Sign())), nil
+			},
+			Decimal: func(_ EvalContext, v Decimal) (Decimal, error) {
+				return NewDecimalFromBig(big.NewInt(int64(v.Sign()))), nil
+			},
+			Float: func(v float64) (float64, error) {
+				switch {
+				case v < 0:
+					return -1, nil
+				case v > 0:
+					return 1, nil
+				}
+				return 0, nil
+			},
+		}),
+		unaryOperator(Sqrt, "square root of a non-negative operand, integer roots are rounded down", unaryFuncs{
+			Int: func(v int64) (int64, int, error) {
+				if v < 0 {
+					return 0, 0, errNegativeSqrt
+				}
+				return new(big.Int).Sqrt(big.NewInt(v)).Int64(), 0, nil
+			},
+			Big: func(v *big.Int) (*big.Int, error) {
+				if v.Sign() < 0 {
+					return nil, errNegativeSqrt
+				}
+				return new(big.Int).Sqrt(v), nil
+			},
+			Decimal: func(ctx EvalContext, v Decimal) (Decimal, error) {
+				if v.Sign() < 0 {
+					return Decimal{}, errNegativeSqrt
+				}
+				// sqrt(u * 10^-s) = sqrt(u * 10^(2k-s)) * 10^-k for the target scale k.
+				scale := ctx.DecimalScale()
+				shifted := v.Rescale(2*scale, DownRounding)
+				return Decimal{unscaled: new(big.Int).Sqrt(shifted.unscaled), scale: scale}, nil
+			},
+			Float: func(v float64) (float64, error) {
+				if v < 0 {
+					return 0, errNegativeSqrt
+				}
+				return math.Sqrt(v), nil
+			},
+		}),
+		variadic(numericOperator(Min, "the least of two or more operands", numericFuncs{
+			Int: func(left, right int64) (int64, int, error) {
+				return min(left, right), 0, nil
+			},
+			Big: func(left, right *big.Int) (*big.Int, error) {
+				if right.Cmp(left) < 0 {
+					return right, nil
+				}
+				return left, nil
+			},
+			Decimal: func(_ EvalContext, left, right Decimal) (Decimal, error) {
+				if right.Cmp(left) < 0 {
+					return right, nil
+				}
+				return left, nil
+			},
+			Float: func(left, right float64) (float64, error) {
+				return math.Min(left, right), nil
+			},
+		}), 2),
+		variadic(numericOperator(Max, "the greatest of two or more operands", numericFuncs{
+			Int: func(left, right int64) (int64, int, error) {
+				return max(left, right), 0, nil
+			},
+			Big: func(left, right *big.Int) (*big.Int, error) {
+				if right.Cmp(left) > 0 {
+					return right, nil
+				}
+				return left, nil
+			},
+			Decimal: func(_ EvalContext, left, right Decimal) (Decimal, error) {
+				if right.Cmp(left) > 0 {
+					return right, nil
+				}
+				return left, nil
+			},
+			Float: func(left, right float64) (float64, error) {
+				return math.Max(left, right), nil
+			},
+		}), 2),
+	}
+}
+
+// divisionOperator builds "/", "floordiv" and "ceildiv", which only differ
+// in how an inexact quotient is rounded. Decimal "/" keeps the configured
+// scale and rounding, the other two produce whole numbers.
+func divisionOperator(symbol CalcAvailableOperation, description string, rounding RoundingMode) Operator {
+	return numericOperator(symbol, description, numericFuncs{
+		Int: func(left, right int64) (int64, int, error) {
+			if right == 0 {
+				return 0, 0, errDivisionByZero
+			}
+			if left == math.MinInt64 && right == -1 {
+				// The quotient is -math.MinInt64, which Go wraps back to math.MinInt64.
+				return math.MinInt64, 1, nil
+			}
+			res := left / right
+			if rem := left % right; rem != 0 {
+				negative := (rem < 0) != (right < 0)
+				if rounding == FloorRounding && negative {
+					res--
+				} else if rounding == CeilingRounding && !negative {
+					res++
+				}
+			}
+			return res, 0, nil
+		},
+		Big: func(left, right *big.Int) (*big.Int, error) {
+			if right.Sign() == 0 {
+				return nil, errDivisionByZero
+			}
+			return divRound(left, right, rounding), nil
+		},
+		Decimal: func(ctx EvalContext, left, right Decimal) (Decimal, error) {
+			if rounding == DownRounding {
+				return left.Quo(right, ctx.DecimalScale(), ctx.Options.DecimalRounding)
+			}
+			return left.Quo(right, 0, rounding)
+		},
+		Float: func(left, right float64) (float64, error) {
+			if right == 0 {
+				return 0, errDivisionByZero
+			}
+			switch rounding {
+			case FloorRounding:
+				return math.Floor(left / right), nil
+			case CeilingRounding:
+				return math.Ceil(left / right), nil
+			}
+			return left / right, nil
+		},
+	})
+}
+
+// mulInt returns the wrapped product and its overflow direction.
+func mulInt(left, right int64) (int64, int) {
+	res := left * right
+	if left != 0 && right != 0 &&
+		(res/right != left || (left == -1 && right == math.MinInt64) || (right == -1 && left == math.MinInt64)) {
+		if (left < 0) != (right < 0) {
+			return res, -1
+		}
+		return res, 1
+	}
+	return res, 0
+}
+
+func powInt(left, right int64) (int64, int, error) {
+	if right < 0 {
+		return 0, 0, errNegativeExp
+	}
+	var (
+		res       int64 = 1
+		base            = left
+		overflown bool
+	)
+	for exp := right; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			var direction int
+			res, direction = mulInt(res, base)
+			overflown = overflown || direction != 0
+		}
+		if exp > 1 {
+			var direction int
+			base, direction = mulInt(base, base)
+			overflown = overflown || direction != 0
+		}
+	}
+	if !overflown {
+		return res, 0, nil
+	}
+	if left < 0 && right&1 == 1 {
+		return res, -1, nil
+	}
+	return res, 1, nil
+}
+
+func powDecimal(ctx EvalContext, left, right Decimal) (Decimal, error) {
+	exp, ok := integralDecimal(right)
+	if !ok {
+		return Decimal{}, NewError(InvalidOperandCode, "operator %s requires an integer exponent", Pow)
+	}
+	if exp < 0 {
+		return Decimal{}, errNegativeExp
+	}
+	if exp > maxShift {
+		return Decimal{}, errTooLarge
+	}
+	res := NewDecimalFromBig(big.NewInt(1))
+	for base := left; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			res = res.Mul(base).Rescale(ctx.DecimalScale(), ctx.Options.DecimalRounding)
+		}
+		if exp > 1 {
+			base = base.Mul(base).Rescale(ctx.DecimalScale(), ctx.Options.DecimalRounding)
+		}
+		if res.unscaled.BitLen() > maxBits || base.unscaled.BitLen() > maxBits {
+			return Decimal{}, errTooLarge
+		}
+	}
+	return res, nil
+}
+
+// integralDecimal returns d as an int64 if it has no fractional part.
+func integralDecimal(d Decimal) (int64, bool) {
+	i := d.Rescale(0, DownRounding)
+	if i.Cmp(d) != 0 || !i.unscaled.IsInt64() {
+		return 0, false
+	}
+	return i.unscaled.Int64(), true
+}
@@ -0,0 +1,259 @@
+package common
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// applyInt64 applies the operator of the operation to int64 operands.
+func applyInt64(operation Operation, left, right int64, mode OverflowMode) (Value, error) {
+	ctx := EvalContext{Var: operation.Var, Options: Options{Overflow: mode}}
+	return applyOperator(ctx, operation.Op, NewIntValue(left), NewIntValue(right))
+}
+
+func TestApplyOperator_IntOverflow(t *testing.T) {
+	tests := []struct {
+		name       string
+		op         CalcAvailableOperation
+		left       int64
+		right      int64
+		wrapping   int64
+		saturating int64
+		underflow  bool
+	}{
+		{"add overflow", Add, math.MaxInt64, 1, math.MinInt64, math.MaxInt64, false},
+		{"add underflow", Add, math.MinInt64, -1, math.MaxInt64, math.MinInt64, true},
+		{"sub overflow", Sub, math.MaxInt64, -1, math.MinInt64, math.MaxInt64, false},
+		{"sub underflow", Sub, math.MinInt64, 1, math.MaxInt64, math.MinInt64, true},
+		{"mul overflow", Mul, math.MaxInt64, 2, -2, math.MaxInt64, false},
+		{"mul underflow", Mul, math.MinInt64, 2, 0, math.MinInt64, true},
+		{"mul negate min", Mul, math.MinInt64, -1, math.MinInt64, math.MaxInt64, false},
+		{"div negate min", Div, math.MinInt64, -1, math.MinInt64, math.MaxInt64, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation := Operation{Type: CalcOperation, Var: "x", Op: tt.op}
+
+			res, err := applyInt64(operation, tt.left, tt.right, WrappingOverflow)
+			assert.NoError(t, err)
+			assert.Equal(t, NewIntValue(tt.wrapping), res)
+
+			res, err = applyInt64(operation, tt.left, tt.right, SaturatingOverflow)
+			assert.NoError(t, err)
+			assert.Equal(t, NewIntValue(tt.saturating), res)
+
+			_, err = applyInt64(operation, tt.left, tt.right, CheckedOverflow)
+			assert.Equal(t, &OverflowError{
+				Var:       "x",
+				Operands:  []int64{tt.left, tt.right},
+				Op:        tt.op,
+				Underflow: tt.underflow,
+			}, err)
+		})
+	}
+}
+
+func TestApplyOperator_IntNoOverflow(t *testing.T) {
+	operation := Operation{Type: CalcOperation, Var: "x", Op: Mul}
+
+	res, err := applyInt64(operation, -3, math.MaxInt64/3, CheckedOverflow)
+	assert.NoError(t, err)
+	assert.Equal(t, NewIntValue(-(math.MaxInt64/3)*3), res)
+
+	operation.Op = Sub
+	res, err = applyInt64(operation, -1, math.MaxInt64, CheckedOverflow)
+	assert.NoError(t, err)
+	assert.Equal(t, NewIntValue(math.MinInt64), res)
+}
+
+func TestApplyOperator_VariadicIntOverflow(t *testing.T) {
+	for _, mode := range []OverflowMode{WrappingOverflow, CheckedOverflow, SaturatingOverflow} {
+		ctx := EvalContext{Var: "x", Options: Options{Overflow: mode}}
+
+		res, err := applyOperator(ctx, Sum, NewIntValue(math.MaxInt64), NewIntValue(1), NewIntValue(-1))
+		assert.NoError(t, err, mode)
+		assert.Equal(t, NewIntValue(math.MaxInt64), res, mode)
+
+		res, err = applyOperator(ctx, Product, NewIntValue(math.MinInt64), NewIntValue(-1), NewIntValue(-1))
+		assert.NoError(t, err, mode)
+		assert.Equal(t, NewIntValue(math.MinInt64), res, mode)
+	}
+
+	ctx := EvalContext{Var: "x", Options: Options{Overflow: SaturatingOverflow}}
+	res, err := applyOperator(ctx, Sum, NewIntValue(math.MinInt64), NewIntValue(-1), NewIntValue(-1))
+	assert.NoError(t, err)
+	assert.Equal(t, NewIntValue(math.MinInt64), res)
+
+	ctx.Options.Overflow = WrappingOverflow
+	res, err = applyOperator(ctx, Product, NewIntValue(math.MaxInt64), NewIntValue(2), NewIntValue(3))
+	assert.NoError(t, err)
+	assert.Equal(t, NewIntValue(-6), res)
+
+	ctx.Options.Overflow = CheckedOverflow
+	_, err = applyOperator(ctx, Product, NewIntValue(math.MinInt64), NewIntValue(-1), NewIntValue(1))
+	assert.Equal(t, &OverflowError{
+		Var:      "x",
+		Operands: []int64{math.MinInt64, -1, 1},
+		Op:       Product,
+	}, err)
+}
+
+func TestApplyOperator_IntExtendedOperators(t *testing.T) {
+	tests := []struct {
+		op       CalcAvailableOperation
+		left     int64
+		right    int64
+		expected int64
+		err      string
+	}{
+		{Mod, 7, 3, 1, ""},
+		{Mod, -7, 3, -1, ""},
+		{Mod, 7, 0, 0, "modulo by zero"},
+		{Pow, 3, 4, 81, ""},
+		{Pow, -2, 3, -8, ""},
+		{Pow, 5, 0, 1, ""},
+		{Pow, 2, -1, 0, "negative exponent"},
+		{And, 12, 10, 8, ""},
+		{Or, 12, 10, 14, ""},
+		{Xor, 12, 10, 6, ""},
+		{Shl, 1, 10, 1024, ""},
+		{Shl, 1, 64, 0, "shift out of range"},
+		{Shr, -16, 2, -4, ""},
+		{Shr, 16, -1, 0, "shift out of range"},
+		{Min, -3, 2, -3, ""},
+		{Max, -3, 2, 2, ""},
+		{FloorDiv, -7, 2, -4, ""},
+		{FloorDiv, 7, 2, 3, ""},
+		{CeilDiv, 7, 2, 4, ""},
+		{CeilDiv, -7, 2, -3, ""},
+		{FloorDiv, 1, 0, 0, "division by zero"},
+	}
+
+	for _, tt := range tests {
+		operation := Operation{Type: CalcOperation, Var: "x", Op: tt.op}
+		res, err := applyInt64(operation, tt.left, tt.right, CheckedOverflow)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, "%d %s %d", tt.left, tt.op, tt.right)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, NewIntValue(tt.expected), res, "%d %s %d", tt.left, tt.op, tt.right)
+	}
+
+	operation := Operation{Type: CalcOperation, Var: "x", Op: Pow}
+	_, err := applyInt64(operation, 2, 63, CheckedOverflow)
+	assert.Equal(t, &OverflowError{Var: "x", Operands: []int64{2, 63}, Op: Pow}, err)
+	res, err := applyInt64(operation, -2, 63, CheckedOverflow)
+	assert.NoError(t, err)
+	assert.Equal(t, NewIntValue(math.MinInt64), res)
+
+	operation.Op = Shl
+	res, err = applyInt64(operation, -3, 63, SaturatingOverflow)
+	assert.NoError(t, err)
+	assert.Equal(t, NewIntValue(math.MinInt64), res)
+	res, err = applyInt64(operation, 3, 62, SaturatingOverflow)
+	assert.NoError(t, err)
+	assert.Equal(t, NewIntValue(math.MaxInt64), res)
+}
+
+func TestApplyOperator_BigLimits(t *testing.T) {
+	ctx := EvalContext{Var: "x"}
+
+	res, err := applyOperator(ctx, Pow, NewBigValue(big.NewInt(3)), NewBigValue(big.NewInt(4)))
+	assert.NoError(t, err)
+	assert.Equal(t, "81", res.String())
+
+	_, err = applyOperator(ctx, Pow, NewBigValue(big.NewInt(3)), NewBigValue(big.NewInt(1<<62)))
+	assert.EqualError(t, err, "result is too large")
+
+	large := NewBigValue(new(big.Int).Lsh(big.NewInt(1), maxBits-1))
+	_, err = applyOperator(ctx, Mul, large, large)
+	assert.EqualError(t, err, "result is too large")
+	_, err = applyOperator(ctx, Shl, large, NewBigValue(big.NewInt(1)))
+	assert.EqualError(t, err, "result is too large")
+	_, err = applyOperator(ctx, Product, NewBigValue(big.NewInt(2)), large, NewBigValue(big.NewInt(1)))
+	assert.EqualError(t, err, "result is too large")
+	res, err = applyOperator(ctx, Sub, large, NewBigValue(big.NewInt(1)))
+	assert.NoError(t, err)
+	assert.Equal(t, maxBits-1, res.Big().BitLen())
+
+	base, _ := ParseDecimal("1e4000")
+	exp, _ := ParseDecimal("1000000")
+	_, err = applyOperator(ctx, Pow, NewDecimalValue(base), NewDecimalValue(exp))
+	assert.EqualError(t, err, "result is too large")
+}
+
+func TestApplyOperator_ExtendedOperatorsOnFractions(t *testing.T) {
+	res, err := applyOperator(EvalContext{}, Mod, NewFloatValue(7.5), NewIntValue(2))
+	assert.NoError(t, err)
+	assert.Equal(t, NewFloatValue(1.5), res)
+
+	_, err = applyOperator(EvalContext{}, Shl, NewFloatValue(1.5), NewIntValue(2))
+	assert.EqualError(t, err, "operator << does not accept float operands")
+
+	left, _ := ParseDecimal("-7.5")
+	right, _ := ParseDecimal("2")
+	res, err = applyOperator(EvalContext{}, FloorDiv, NewDecimalValue(left), NewDecimalValue(right))
+	assert.NoError(t, err)
+	assert.Equal(t, "-4.00", res.String())
+
+	res, err = applyOperator(EvalContext{}, Pow, NewDecimalValue(left), NewDecimalValue(right))
+	assert.NoError(t, err)
+	assert.Equal(t, "56.25", res.String())
+
+	half, _ := ParseDecimal("0.5")
+	_, err = applyOperator(EvalContext{}, Pow, NewDecimalValue(left), NewDecimalValue(half))
+	assert.EqualError(t, err, "operator ** requires an integer exponent")
+}
+
+func TestApplyOperator_UnaryAndVariadic(t *testing.T) {
+	ctx := EvalContext{Var: "x", Options: Options{Overflow: CheckedOverflow}}
+
+	res, err := applyOperator(ctx, Sum, NewIntValue(1), NewIntValue(2), NewIntValue(3), NewIntValue(4))
+	assert.NoError(t, err)
+	assert.Equal(t, NewIntValue(10), res)
+
+	res, err = applyOperator(ctx, Sum, NewIntValue(7))
+	assert.NoError(t, err)
+	assert.Equal(t, NewIntValue(7), res)
+
+	res, err = applyOperator(ctx, Max, NewIntValue(1), NewFloatValue(2.5), NewIntValue(-3))
+	assert.NoError(t, err)
+	assert.Equal(t, NewFloatValue(2.5), res)
+
+	res, err = applyOperator(ctx, Abs, NewIntValue(-5))
+	assert.NoError(t, err)
+	assert.Equal(t, NewIntValue(5), res)
+
+	res, err = applyOperator(ctx, Sign, NewIntValue(-5))
+	assert.NoError(t, err)
+	assert.Equal(t, NewIntValue(-1), res)
+
+	res, err = applyOperator(ctx, Sqrt, NewIntValue(17))
+	assert.NoError(t, err)
+	assert.Equal(t, NewIntValue(4), res)
+
+	two, _ := ParseDecimal("2")
+	res, err = applyOperator(ctx, Sqrt, NewDecimalValue(two))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.41", res.String())
+
+	_, err = applyOperator(ctx, Sqrt, NewIntValue(-1))
+	assert.EqualError(t, err, "square root of a negative number")
+
+	_, err = applyOperator(ctx, Neg, NewIntValue(math.MinInt64))
+	assert.EqualError(t, err, "integer overflow computing variable 'x': neg(-9223372036854775808)")
+
+	_, err = applyOperator(ctx, Sum, NewIntValue(math.MaxInt64), NewIntValue(1), NewIntValue(5))
+	assert.EqualError(t, err, "integer overflow computing variable 'x': sum(9223372036854775807, 1, 5)")
+
+	_, err = applyOperator(ctx, Neg, NewIntValue(1), NewIntValue(2))
+	assert.EqualError(t, err, "operator neg expects 1 operands, got 2")
+
+	_, err = applyOperator(ctx, Min, NewIntValue(1))
+	assert.EqualError(t, err, "operator min expects at least 2 operands, got 1")
+}
@@ -2,9 +2,11 @@ package common
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"log/slog"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 	"upgraded-calculator/internal/config"
@@ -13,11 +15,20 @@ import (
 type UpgradedCalculator struct {
 	logger    *slog.Logger
 	requestId string
-	variables map[string]int64
-	subs      map[string][]chan int64
-	mutex     sync.Mutex
+	variables map[string]Value
+	// inputs holds the variables defined from the inputs of the request.
+	inputs map[string]bool
+	subs   map[string][]chan Value
+	mutex  sync.Mutex
+
+	observer   Observer
+	observerMu sync.Mutex
 }
 
+// Observer is called with every Event of a run as soon as it happens. Calls
+// are serialized, a slow observer holds back the run.
+type Observer func(Event)
+
 func NewUpgradedCalculator(
 	logger *slog.Logger,
 	requestId string,
@@ -25,140 +36,490 @@ func NewUpgradedCalculator(
 	return &UpgradedCalculator{
 		logger:    logger,
 		requestId: requestId,
-		variables: make(map[string]int64),
-		subs:      make(map[string][]chan int64),
+		variables: make(map[string]Value),
+		inputs:    make(map[string]bool),
+		subs:      make(map[string][]chan Value),
+	}
+}
+
+// Observe makes the calculator report prints and failures to the observer
+// while Run is in progress, in addition to the returned Result.
+func (c *UpgradedCalculator) Observe(observer Observer) {
+	c.observerMu.Lock()
+	defer c.observerMu.Unlock()
+	c.observer = observer
+}
+
+func (c *UpgradedCalculator) emit(event Event) {
+	c.observerMu.Lock()
+	defer c.observerMu.Unlock()
+	if c.observer != nil {
+		c.observer(event)
 	}
 }
 
+// taskResult is reported by a worker once it has finished an operation.
+type taskResult struct {
+	index int
+	err   error
+	// needs is set instead of err when a cond operation has to wait for the
+	// variable of the branch it took. The operation is dispatched again
+	// once that variable is published.
+	needs string
+}
+
+// Execute runs the operations in dependency order. Operations are handed to
+// the workers only when every variable they read has already been published,
+// so no worker ever blocks waiting for another one and any worker count
+// evaluates every valid program.
 func (c *UpgradedCalculator) Execute(cont context.Context, operations []Operation) ([]PrintOutput, error) {
+	return c.ExecuteWithOptions(cont, operations, Options{})
+}
+
+// ExecuteWithOptions behaves like Execute and applies the given options.
+// Prints are listed in the order of the print operations in the request
+// unless completion order is requested, in which case every print carries
+// its sequence number.
+func (c *UpgradedCalculator) ExecuteWithOptions(
+	cont context.Context,
+	operations []Operation,
+	opts Options,
+) ([]PrintOutput, error) {
+	result, err := c.Run(cont, operations, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+// Run behaves like ExecuteWithOptions and also reports which assignments
+// were computed. In the lazy evaluation mode only the operations that
+// print operations transitively depend on are computed, the rest are
+// reported as skipped. When errors are collected, failed operations are
+// reported in Result.Errors instead of failing the whole execution.
+func (c *UpgradedCalculator) Run(
+	cont context.Context,
+	operations []Operation,
+	opts Options,
+) (Result, error) {
 	var (
-		result      []PrintOutput
+		prints      []*PrintOutput
+		completed   []PrintOutput
 		resultMu    sync.Mutex
 		wg          sync.WaitGroup
-		errorsCh    = make(chan error, 1)
 		ctx, cancel = context.WithCancel(cont)
 		cfg         = config.New()
 	)
 	defer cancel()
-	defer close(errorsCh)
+
+	c.logger.Debug("Operations to execute", "request_id", c.requestId, "length", len(operations))
+
+	if err := opts.Validate(); err != nil {
+		return Result{}, err
+	}
+	if err := c.defineInputs(opts); err != nil {
+		return Result{}, err
+	}
+
+	requested := operations
+	operations, origins, err := expandExpressions(operations)
+	if err != nil {
+		return Result{}, err
+	}
+
+	prints = make([]*PrintOutput, len(operations))
+
+	graph, err := buildDependencyGraph(operations, c.defined)
+	if err != nil {
+		c.logger.Debug("Static analysis failed", "request_id", c.requestId, "error", err)
+		return Result{}, relocate(err, origins, requested)
+	}
 
 	workerCount := cfg.App.CalculatorWorkersCount
 	if workerCount <= 0 {
 		workerCount = 1
 	}
-	tasksCh := make(chan Operation, len(operations))
-
-	c.logger.Debug("Operations to execute", "request_id", c.requestId, "length", len(operations))
+	if workerCount > len(operations) {
+		workerCount = len(operations)
+	}
+	tasksCh := make(chan int, len(operations))
+	doneCh := make(chan taskResult, len(operations))
 
 	wg.Add(workerCount)
 	for i := 0; i < workerCount; i++ {
 		go func(workerID int) {
 			defer wg.Done()
-			for op := range tasksCh {
+			for index := range tasksCh {
 				select {
 				case <-ctx.Done():
 					return
 				default:
-					var err error
-
-					switch op.Type {
-					case CalcOperation:
-						err = c.compute(op)
-						c.logger.Debug("Compute operation", "request_id", c.requestId, "operation", op)
-					case PrintOperation:
-						var value int64
-						value, err = c.subscribeVariable(op.Var)
-						if err == nil {
-							resultMu.Lock()
-							result = append(result, PrintOutput{
-								Var:   op.Var,
-								Value: value,
-							})
-							resultMu.Unlock()
-						}
-						c.logger.Debug("Print operation", "request_id", c.requestId, "operation", op)
-					default:
-						err = errors.New("invalid operation")
-					}
+				}
 
-					if err != nil {
-						select {
-						case errorsCh <- err:
-							cancel()
-						default:
+				var (
+					err   error
+					needs string
+				)
+				op := operations[index]
+				switch op.Type {
+				case CalcOperation:
+					err = c.compute(op, opts)
+					c.logger.Debug("Compute operation", "request_id", c.requestId, "worker", workerID, "operation", op)
+				case CondOperation:
+					needs, err = c.choose(op, opts)
+					c.logger.Debug("Conditional operation", "request_id", c.requestId, "worker", workerID, "operation", op, "needs", needs)
+				case PrintOperation:
+					var value Value
+					value, err = c.subscribeVariable(op.Var)
+					if err == nil {
+						output := PrintOutput{Var: op.Var, Value: value}
+						resultMu.Lock()
+						if opts.PrintOrder == CompletionPrintOrder {
+							seq := int64(len(completed) + 1)
+							output.Seq = &seq
+							completed = append(completed, output)
+						} else {
+							prints[index] = &output
 						}
+						// Emitting under resultMu keeps completion order.
+						c.emit(Event{Print: &output})
+						resultMu.Unlock()
 					}
+					c.logger.Debug("Print operation", "request_id", c.requestId, "worker", workerID, "operation", op)
+				default:
+					err = NewError(InvalidOperationCode, "invalid operation")
+				}
+				if err != nil {
+					err = atOperation(err, index, op.Var)
 				}
+				doneCh <- taskResult{index: index, err: err, needs: needs}
 			}
 		}(i)
 	}
-	go func() {
-		defer close(tasksCh)
-		for _, op := range operations {
-			select {
-			case <-ctx.Done():
-				break
-			case tasksCh <- op:
-			}
+
+	variable := func(source int) string {
+		return requested[origins[source]].Var
+	}
+	// Operations of an expression share the index and the variable of the
+	// expr operation, only the first failure among them is reported.
+	observed := make(map[int]bool)
+	report := func(index int, err error) {
+		if observed[origins[index]] {
+			return
 		}
-	}()
+		observed[origins[index]] = true
+		opErr := newOperationError(origins[index], requested[origins[index]].Var, err, variable)
+		c.emit(Event{Error: &opErr})
+	}
 
+	collect := opts.Errors == CollectErrors
+	done, failed, err := c.schedule(ctx, graph, graph.roots(opts.Evaluation), collect, report, tasksCh, doneCh)
+	close(tasksCh)
+	cancel()
 	wg.Wait()
 
+	if err != nil {
+		return Result{}, relocate(err, origins, requested)
+	}
 	c.logger.Debug("All operations executed", "request_id", c.requestId)
-	select {
-	case err := <-errorsCh:
-		return nil, err
-	default:
+
+	result := Result{Summary: Summary{Skipped: []string{}}}
+	for i, op := range operations {
+		if !op.assigns() || temporaryVariable(op.Var) {
+			continue
+		}
+		if done[i] {
+			result.Summary.Computed++
+		} else if failed[i] == nil {
+			result.Summary.Skipped = append(result.Summary.Skipped, op.Var)
+		}
+	}
+
+	reported := make(map[int]bool)
+	for i, err := range failed {
+		if err == nil || reported[origins[i]] {
+			continue
+		}
+		reported[origins[i]] = true
+		result.Errors = append(result.Errors, newOperationError(origins[i], requested[origins[i]].Var, err, variable))
+	}
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Index < result.Errors[j].Index
+	})
+
+	if opts.PrintOrder == CompletionPrintOrder {
+		result.Items = completed
 		return result, nil
 	}
+	for _, output := range prints {
+		if output != nil {
+			result.Items = append(result.Items, *output)
+		}
+	}
+	return result, nil
+}
+
+// schedule dispatches every demanded operation whose inputs are ready and
+// releases its dependents as workers report completions. Demanding an
+// operation demands the producers of its inputs, and a cond operation
+// demands the producer of the branch it took, so deferred operations only
+// run when their result is needed.
+//
+// It reports which operations completed. Unless errors are collected, it
+// returns the first error reported by a worker instead. When they are,
+// a failure fails every demanded operation depending on it with an
+// upstreamError and the other operations go on, the errors are reported
+// per operation and every failure is passed to report as it happens. The
+// context error is returned if execution was cancelled.
+func (c *UpgradedCalculator) schedule(
+	ctx context.Context,
+	graph *dependencyGraph,
+	roots []int,
+	collect bool,
+	report func(index int, err error),
+	tasksCh chan<- int,
+	doneCh <-chan taskResult,
+) ([]bool, []error, error) {
+	var (
+		pending     = graph.pendingCounts()
+		demanded    = make([]bool, len(pending))
+		done        = make([]bool, len(pending))
+		failed      = make([]error, len(pending))
+		waiting     = make([][]int, len(pending))
+		outstanding int
+	)
+
+	var fail func(index int, err error)
+	fail = func(index int, err error) {
+		if failed[index] != nil || done[index] {
+			return
+		}
+		failed[index] = err
+		outstanding--
+		report(index, err)
+
+		upstream := &upstreamError{source: sourceOf(index, err)}
+		for _, dependents := range [][]int{graph.dependents[index], waiting[index]} {
+			for _, dependent := range dependents {
+				if demanded[dependent] {
+					fail(dependent, upstream)
+				}
+			}
+		}
+	}
+
+	var demand func(index int)
+	demand = func(index int) {
+		if demanded[index] {
+			return
+		}
+		demanded[index] = true
+		outstanding++
+		for _, name := range graph.deps[index] {
+			producer := graph.producers[name]
+			demand(producer)
+			if failed[producer] != nil {
+				fail(index, &upstreamError{source: sourceOf(producer, failed[producer])})
+				return
+			}
+		}
+		if pending[index] == 0 && failed[index] == nil {
+			tasksCh <- index
+		}
+	}
+	for _, index := range roots {
+		demand(index)
+	}
+
+	for outstanding > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case res := <-doneCh:
+			if res.err != nil {
+				if !collect {
+					return nil, nil, res.err
+				}
+				fail(res.index, res.err)
+				continue
+			}
+			if res.needs != "" {
+				producer := graph.producers[res.needs]
+				if done[producer] {
+					tasksCh <- res.index
+				} else if failed[producer] != nil {
+					fail(res.index, &upstreamError{source: sourceOf(producer, failed[producer])})
+				} else {
+					waiting[producer] = append(waiting[producer], res.index)
+					demand(producer)
+				}
+				continue
+			}
+
+			done[res.index] = true
+			outstanding--
+			for _, waiter := range waiting[res.index] {
+				tasksCh <- waiter
+			}
+			for _, dependent := range graph.dependents[res.index] {
+				pending[dependent]--
+				if pending[dependent] == 0 && demanded[dependent] && failed[dependent] == nil {
+					tasksCh <- dependent
+				}
+			}
+		}
+	}
+	return done, failed, nil
 }
 
-func (c *UpgradedCalculator) compute(operation Operation) error {
-	leftValue, err := c.getOperandValue(*operation.Left)
+func (c *UpgradedCalculator) compute(operation Operation, opts Options) error {
+	operands := operation.Operands()
+	values := make([]Value, len(operands))
+	for i, operand := range operands {
+		value, err := c.getOperandValue(*operand, opts)
+		if err != nil {
+			return err
+		}
+		c.logger.Debug("Operand value", "index", i, "value", value)
+		values[i] = value
+	}
+
+	res, err := applyOperator(EvalContext{Var: operation.Var, Options: opts}, operation.Op, values...)
 	if err != nil {
 		return err
 	}
-	c.logger.Debug("Operand value", "left", leftValue)
 
-	rightValue, err := c.getOperandValue(*operation.Right)
+	return c.publishVariable(operation.Var, res)
+}
+
+// choose evaluates the condition of a cond operation and publishes the
+// chosen branch. If the branch is a variable that is not computed yet, it
+// publishes nothing and returns the name of the variable to wait for.
+func (c *UpgradedCalculator) choose(operation Operation, opts Options) (string, error) {
+	cond, err := c.getOperandValue(*operation.Cond, opts)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	c.logger.Debug("Operand value", "right", rightValue)
+	branch := operation.Then
+	if cond.IsZero() {
+		branch = operation.Else
+	}
+	if branch.StringValue != nil {
+		c.mutex.Lock()
+		_, exists := c.variables[*branch.StringValue]
+		c.mutex.Unlock()
+		if !exists {
+			return *branch.StringValue, nil
+		}
+	}
+
+	value, err := c.getOperandValue(*branch, opts)
+	if err != nil {
+		return "", err
+	}
+	return "", c.publishVariable(operation.Var, value)
+}
 
-	var res int64
-	switch operation.Op {
-	case "+":
-		res = leftValue + rightValue
-	case "-":
-		res = leftValue - rightValue
-	case "*":
-		res = leftValue * rightValue
-	case "/":
-		if rightValue == 0 {
-			return errors.New("division by zero")
+// getOperandValue resolves the operand and types literals according to the
+// numbers mode of the request.
+func (c *UpgradedCalculator) getOperandValue(op Operand, opts Options) (Value, error) {
+	switch {
+	case op.IntValue != nil:
+		switch opts.Numbers {
+		case BigNumbers:
+			return NewBigValue(big.NewInt(*op.IntValue)), nil
+		case DecimalNumbers:
+			return NewDecimalValue(NewDecimalFromBig(big.NewInt(*op.IntValue))), nil
+		}
+		return NewIntValue(*op.IntValue), nil
+	case op.BigValue != nil:
+		switch opts.Numbers {
+		case BigNumbers:
+			return NewBigValue(op.BigValue), nil
+		case DecimalNumbers:
+			return NewDecimalValue(NewDecimalFromBig(op.BigValue)), nil
+		}
+		return Value{}, NewError(InvalidOperandCode, "operand %s does not fit into int64, use the big numbers mode", op.BigValue)
+	case op.DecimalValue != nil:
+		if opts.Numbers == DecimalNumbers {
+			if op.DecimalValue.Scale() > MaxDecimalScale {
+				return Value{}, NewError(InvalidOperandCode, "decimal operand has %d fractional digits, at most %d are allowed", op.DecimalValue.Scale(), MaxDecimalScale)
+			}
+			return NewDecimalValue(*op.DecimalValue), nil
 		}
-		res = leftValue / rightValue
-	default:
-		return errors.New("invalid operation")
+		return finiteFloatValue(op.DecimalValue.Float64(), op.DecimalValue.String())
+	case op.FloatValue != nil:
+		return finiteFloatValue(*op.FloatValue, strconv.FormatFloat(*op.FloatValue, 'g', -1, 64))
+	case op.StringValue != nil:
+		return c.subscribeVariable(*op.StringValue)
 	}
+	return Value{}, NewError(InvalidOperandCode, "invalid operand")
+}
 
-	return c.publishVariable(operation.Var, res)
+// finiteFloatValue rejects literals that are infinite or NaN as floats, the
+// operators never produce such values either.
+func finiteFloatValue(f float64, literal string) (Value, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return Value{}, NewError(InvalidOperandCode, "operand %s is out of the float range", literal)
+	}
+	return NewFloatValue(f), nil
 }
 
-func (c *UpgradedCalculator) getOperandValue(op Operand) (int64, error) {
-	if op.IntValue != nil {
-		return *op.IntValue, nil
+// Define assigns the variable before Run, so that operations can read it.
+// Operations cannot assign a defined variable.
+func (c *UpgradedCalculator) Define(name string, value Value) error {
+	return c.publishVariable(name, value)
+}
+
+// defineInputs defines the inputs of the request in the order of their
+// names. An input cannot replace a defined variable.
+func (c *UpgradedCalculator) defineInputs(opts Options) error {
+	names := make([]string, 0, len(opts.Inputs))
+	for name := range opts.Inputs {
+		names = append(names, name)
 	}
-	if op.StringValue != nil {
-		return c.subscribeVariable(*op.StringValue)
+	sort.Strings(names)
+	for _, name := range names {
+		value, err := c.getOperandValue(opts.Inputs[name], opts)
+		if err != nil {
+			return &Error{Code: CodeOf(err), Message: err.Error(), Field: "inputs." + name, Err: err}
+		}
+		if c.defined(name) {
+			return NewError(DuplicateAssignmentCode, "input '%s' is already assigned", name).WithField("inputs." + name)
+		}
+		if err := c.publishVariable(name, value); err != nil {
+			return err
+		}
+		c.mutex.Lock()
+		c.inputs[name] = true
+		c.mutex.Unlock()
 	}
-	return 0, errors.New("invalid operand")
+	return nil
+}
+
+// Variables returns the variables assigned so far, without the intermediate
+// results of expressions and the inputs of the request.
+func (c *UpgradedCalculator) Variables() map[string]Value {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	variables := make(map[string]Value, len(c.variables))
+	for name, value := range c.variables {
+		if !temporaryVariable(name) && !c.inputs[name] {
+			variables[name] = value
+		}
+	}
+	return variables
+}
+
+func (c *UpgradedCalculator) defined(name string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	_, exists := c.variables[name]
+	return exists
 }
 
-func (c *UpgradedCalculator) subscribeVariable(name string) (int64, error) {
+func (c *UpgradedCalculator) subscribeVariable(name string) (Value, error) {
 	c.mutex.Lock()
 	if val, exists := c.variables[name]; exists {
 		c.mutex.Unlock()
@@ -166,7 +527,7 @@ func (c *UpgradedCalculator) subscribeVariable(name string) (int64, error) {
 	}
 	c.mutex.Unlock()
 
-	ch := make(chan int64, 1)
+	ch := make(chan Value, 1)
 
 	c.mutex.Lock()
 	c.subs[name] = append(c.subs[name], ch)
@@ -187,16 +548,16 @@ func (c *UpgradedCalculator) subscribeVariable(name string) (int64, error) {
 			delete(c.subs, name)
 		}
 		c.mutex.Unlock()
-		return 0, fmt.Errorf("variable '%s' is uncomputable", name)
+		return Value{}, NewError(TimeoutCode, "variable '%s' is uncomputable", name)
 	}
 }
 
-func (c *UpgradedCalculator) publishVariable(name string, value int64) error {
+func (c *UpgradedCalculator) publishVariable(name string, value Value) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
 	if _, exists := c.variables[name]; exists {
-		return fmt.Errorf("variable %s already set", name)
+		return NewError(DuplicateAssignmentCode, "variable %s already set", name)
 	}
 
 	c.variables[name] = value
@@ -1,12 +1,22 @@
 package common
 
 import (
+	"context"
+	"encoding/json"
+	"io"
 	"log/slog"
+	"maps"
+	"math"
+	"math/big"
 	"os"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Helper function to create pointers for int64
@@ -14,6 +24,11 @@ func int64Ptr(i int64) *int64 {
 	return &i
 }
 
+// Helper function to create pointers for strings
+func stringPtr(s string) *string {
+	return &s
+}
+
 func TestUpgradedCalculator_ComputeOperations(t *testing.T) {
 	logger := slog.New(
 		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
@@ -43,10 +58,10 @@ func TestUpgradedCalculator_ComputeOperations(t *testing.T) {
 	}
 
 	expectedOutputs := []PrintOutput{
-		{Var: "x", Value: 3},
+		{Var: "x", Value: NewIntValue(3)},
 	}
 
-	actualOutputs, err := calculator.Execute(operations)
+	actualOutputs, err := calculator.Execute(context.Background(), operations)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedOutputs, actualOutputs)
 }
@@ -68,7 +83,7 @@ func TestUpgradedCalculator_DivisionByZero(t *testing.T) {
 		},
 	}
 
-	_, err := calculator.Execute(operations)
+	_, err := calculator.Execute(context.Background(), operations)
 	assert.Error(t, err)
 	assert.Equal(t, "division by zero", err.Error())
 }
@@ -87,10 +102,10 @@ func TestUpgradedCalculator_SubscribeVariable(t *testing.T) {
 		defer wg.Done()
 		value, err := calculator.subscribeVariable("y")
 		assert.NoError(t, err)
-		assert.Equal(t, int64(100), value)
+		assert.Equal(t, NewIntValue(100), value)
 	}()
 
-	err := calculator.publishVariable("y", 100)
+	err := calculator.publishVariable("y", NewIntValue(100))
 	assert.NoError(t, err)
 
 	wg.Wait()
@@ -113,7 +128,599 @@ func TestUpgradedCalculator_InvalidOperation(t *testing.T) {
 		},
 	}
 
-	_, err := calculator.Execute(operations)
+	_, err := calculator.Execute(context.Background(), operations)
 	assert.Error(t, err)
 	assert.Equal(t, "invalid operation", err.Error())
 }
+
+func TestUpgradedCalculator_InvalidVariableName(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	one := &Operand{IntValue: int64Ptr(1)}
+	valid := Operation{Type: CalcOperation, Var: "a", Op: Add, Left: one, Right: one}
+	tests := []Operation{
+		{Type: CalcOperation, Var: "x$0.1", Op: Add, Left: one, Right: one},
+		{Type: CondOperation, Var: "", Cond: one, Then: one, Else: one},
+		{Type: ExprOperation, Var: "a1", Expr: "1 + 2"},
+		{Type: PrintOperation, Var: "a$0.1"},
+	}
+	for _, op := range tests {
+		calculator := NewUpgradedCalculator(logger, "invalid_variable_name")
+		_, err := calculator.Execute(context.Background(), []Operation{valid, op})
+		var located *Error
+		require.ErrorAs(t, err, &located, op.Type)
+		assert.Equal(t, InvalidOperationCode, located.Code, op.Type)
+		assert.Equal(t, "var", located.Field, op.Type)
+		require.NotNil(t, located.Index, op.Type)
+		assert.Equal(t, 1, *located.Index, op.Type)
+
+		session, err := NewSession(calculator, Options{})
+		require.NoError(t, err)
+		assert.ErrorAs(t, session.Add(0, op), &located, op.Type)
+	}
+}
+
+func TestUpgradedCalculator_UndefinedVariable(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	calculator := NewUpgradedCalculator(logger, "undefined_variable")
+
+	operations := []Operation{
+		{
+			Type:  CalcOperation,
+			Var:   "x",
+			Left:  &Operand{StringValue: stringPtr("y")},
+			Right: &Operand{IntValue: int64Ptr(1)},
+			Op:    "+",
+		},
+	}
+
+	start := time.Now()
+	_, err := calculator.Execute(context.Background(), operations)
+	assert.Error(t, err)
+	assert.Equal(t, "variable 'y' is undefined", err.Error())
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestUpgradedCalculator_DependencyCycle(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	calculator := NewUpgradedCalculator(logger, "dependency_cycle")
+
+	operations := []Operation{
+		{
+			Type:  CalcOperation,
+			Var:   "x",
+			Left:  &Operand{StringValue: stringPtr("y")},
+			Right: &Operand{IntValue: int64Ptr(1)},
+			Op:    "+",
+		},
+		{
+			Type:  CalcOperation,
+			Var:   "y",
+			Left:  &Operand{StringValue: stringPtr("z")},
+			Right: &Operand{IntValue: int64Ptr(1)},
+			Op:    "+",
+		},
+		{
+			Type:  CalcOperation,
+			Var:   "z",
+			Left:  &Operand{StringValue: stringPtr("x")},
+			Right: &Operand{IntValue: int64Ptr(1)},
+			Op:    "+",
+		},
+	}
+
+	_, err := calculator.Execute(context.Background(), operations)
+	assert.Error(t, err)
+	assert.Equal(t, "dependency cycle detected: x -> y -> z -> x", err.Error())
+}
+
+func TestUpgradedCalculator_DuplicateAssignment(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	calculator := NewUpgradedCalculator(logger, "duplicate_assignment")
+
+	operations := []Operation{
+		{
+			Type:  CalcOperation,
+			Var:   "x",
+			Left:  &Operand{IntValue: int64Ptr(1)},
+			Right: &Operand{IntValue: int64Ptr(2)},
+			Op:    "+",
+		},
+		{
+			Type:  CalcOperation,
+			Var:   "x",
+			Left:  &Operand{IntValue: int64Ptr(3)},
+			Right: &Operand{IntValue: int64Ptr(4)},
+			Op:    "+",
+		},
+	}
+
+	_, err := calculator.Execute(context.Background(), operations)
+	assert.Error(t, err)
+	assert.Equal(t, "variable 'x' is assigned more than once (operations 0 and 1)", err.Error())
+}
+
+func TestUpgradedCalculator_ForwardReferenceSingleWorker(t *testing.T) {
+	t.Setenv("CALCULATOR_WORKERS", "1")
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	calculator := NewUpgradedCalculator(logger, "forward_reference")
+
+	operations := []Operation{
+		{
+			Type: PrintOperation,
+			Var:  "z",
+		},
+		{
+			Type:  CalcOperation,
+			Var:   "z",
+			Left:  &Operand{StringValue: stringPtr("y")},
+			Right: &Operand{StringValue: stringPtr("x")},
+			Op:    "*",
+		},
+		{
+			Type:  CalcOperation,
+			Var:   "y",
+			Left:  &Operand{StringValue: stringPtr("x")},
+			Right: &Operand{IntValue: int64Ptr(2)},
+			Op:    "+",
+		},
+		{
+			Type:  CalcOperation,
+			Var:   "x",
+			Left:  &Operand{IntValue: int64Ptr(3)},
+			Right: &Operand{IntValue: int64Ptr(4)},
+			Op:    "+",
+		},
+	}
+
+	start := time.Now()
+	actualOutputs, err := calculator.Execute(context.Background(), operations)
+	assert.NoError(t, err)
+	assert.Equal(t, []PrintOutput{{Var: "z", Value: NewIntValue(63)}}, actualOutputs)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestUpgradedCalculator_PrintOrder(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	operations := []Operation{
+		{Type: PrintOperation, Var: "y"},
+		{Type: PrintOperation, Var: "x"},
+		{
+			Type:  CalcOperation,
+			Var:   "y",
+			Left:  &Operand{StringValue: stringPtr("x")},
+			Right: &Operand{IntValue: int64Ptr(1)},
+			Op:    "+",
+		},
+		{
+			Type:  CalcOperation,
+			Var:   "x",
+			Left:  &Operand{IntValue: int64Ptr(1)},
+			Right: &Operand{IntValue: int64Ptr(1)},
+			Op:    "+",
+		},
+		{Type: PrintOperation, Var: "x"},
+	}
+
+	for i := 0; i < 20; i++ {
+		calculator := NewUpgradedCalculator(logger, "print_order")
+		actualOutputs, err := calculator.Execute(context.Background(), operations)
+		assert.NoError(t, err)
+		assert.Equal(t, []PrintOutput{
+			{Var: "y", Value: NewIntValue(3)},
+			{Var: "x", Value: NewIntValue(2)},
+			{Var: "x", Value: NewIntValue(2)},
+		}, actualOutputs)
+	}
+
+	calculator := NewUpgradedCalculator(logger, "completion_order")
+	actualOutputs, err := calculator.ExecuteWithOptions(
+		context.Background(), operations, Options{PrintOrder: CompletionPrintOrder},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, actualOutputs, 3)
+	for i, output := range actualOutputs {
+		assert.Equal(t, int64(i+1), *output.Seq)
+	}
+	assert.Equal(t, "y", actualOutputs[2].Var)
+}
+
+func TestUpgradedCalculator_BigNumbers(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	var operations []Operation
+	err := json.Unmarshal([]byte(`[
+		{"type": "calc", "var": "x", "op": "*", "left": "9223372036854775807", "right": "10"},
+		{"type": "calc", "var": "y", "op": "+", "left": "x", "right": "123456789012345678901234567890"},
+		{"type": "print", "var": "y"}
+	]`), &operations)
+	assert.NoError(t, err)
+
+	calculator := NewUpgradedCalculator(logger, "big_numbers")
+	actualOutputs, err := calculator.ExecuteWithOptions(context.Background(), operations, Options{Numbers: BigNumbers})
+	assert.NoError(t, err)
+	assert.Len(t, actualOutputs, 1)
+	assert.Equal(t, "123456789104579399269782325960", actualOutputs[0].Value.String())
+
+	body, err := json.Marshal(actualOutputs)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"var": "y", "value": "123456789104579399269782325960"}]`, string(body))
+
+	calculator = NewUpgradedCalculator(logger, "big_numbers_int64")
+	_, err = calculator.Execute(context.Background(), operations)
+	assert.Error(t, err)
+}
+
+func TestUpgradedCalculator_FractionalNumbers(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	var operations []Operation
+	err := json.Unmarshal([]byte(`[
+		{"type": "calc", "var": "x", "op": "*", "left": "1.5", "right": 3},
+		{"type": "calc", "var": "y", "op": "/", "left": "x", "right": "7"},
+		{"type": "calc", "var": "z", "op": "/", "left": 7, "right": 2},
+		{"type": "print", "var": "x"},
+		{"type": "print", "var": "y"},
+		{"type": "print", "var": "z"}
+	]`), &operations)
+	assert.NoError(t, err)
+
+	calculator := NewUpgradedCalculator(logger, "float_numbers")
+	actualOutputs, err := calculator.Execute(context.Background(), operations)
+	assert.NoError(t, err)
+	body, err := json.Marshal(actualOutputs)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"var": "x", "value": 4.5},
+		{"var": "y", "value": 0.6428571428571429},
+		{"var": "z", "value": 3}
+	]`, string(body))
+
+	scale := int32(3)
+	calculator = NewUpgradedCalculator(logger, "decimal_numbers")
+	actualOutputs, err = calculator.ExecuteWithOptions(context.Background(), operations, Options{
+		Numbers:         DecimalNumbers,
+		DecimalScale:    &scale,
+		DecimalRounding: DownRounding,
+	})
+	assert.NoError(t, err)
+	body, err = json.Marshal(actualOutputs)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"var": "x", "value": "4.500"},
+		{"var": "y", "value": "0.642"},
+		{"var": "z", "value": "3.500"}
+	]`, string(body))
+
+	err = json.Unmarshal([]byte(`[
+		{"type": "cond", "var": "x", "cond": 1, "then": "1e400", "else": 0},
+		{"type": "print", "var": "x"}
+	]`), &operations)
+	assert.NoError(t, err)
+	calculator = NewUpgradedCalculator(logger, "infinite_literal")
+	_, err = calculator.Execute(context.Background(), operations)
+	assert.Equal(t, InvalidOperandCode, CodeOf(err))
+
+	err = json.Unmarshal([]byte(`[
+		{"type": "calc", "var": "x", "op": "*", "left": "1e-70", "right": "1e70"},
+		{"type": "print", "var": "x"}
+	]`), &operations)
+	assert.NoError(t, err)
+	calculator = NewUpgradedCalculator(logger, "small_float_literal")
+	actualOutputs, err = calculator.Execute(context.Background(), operations)
+	assert.NoError(t, err)
+	assert.Equal(t, []PrintOutput{{Var: "x", Value: NewFloatValue(1e-70 * 1e70)}}, actualOutputs)
+	calculator = NewUpgradedCalculator(logger, "small_decimal_literal")
+	_, err = calculator.ExecuteWithOptions(context.Background(), operations, Options{Numbers: DecimalNumbers})
+	assert.EqualError(t, err, "decimal operand has 70 fractional digits, at most 64 are allowed")
+
+	nan := math.NaN()
+	calculator = NewUpgradedCalculator(logger, "nan_input")
+	_, err = calculator.ExecuteWithOptions(context.Background(), []Operation{{Type: PrintOperation, Var: "a"}}, Options{
+		Inputs: map[string]Operand{"a": {FloatValue: &nan}},
+	})
+	assert.Equal(t, InvalidOperandCode, CodeOf(err))
+}
+
+func TestUpgradedCalculator_ArgsOperations(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	var operations []Operation
+	err := json.Unmarshal([]byte(`[
+		{"type": "calc", "var": "a", "op": "-", "left": 2, "right": 9},
+		{"type": "calc", "var": "b", "op": "abs", "args": ["a"]},
+		{"type": "calc", "var": "c", "op": "sum", "args": ["a", "b", 10, "b"]},
+		{"type": "print", "var": "c"}
+	]`), &operations)
+	assert.NoError(t, err)
+
+	calculator := NewUpgradedCalculator(logger, "args_operations")
+	actualOutputs, err := calculator.Execute(context.Background(), operations)
+	assert.NoError(t, err)
+	assert.Equal(t, []PrintOutput{{Var: "c", Value: NewIntValue(17)}}, actualOutputs)
+
+	err = json.Unmarshal([]byte(`[{"type": "calc", "var": "a", "op": "abs", "args": [1, 2]}]`), &operations)
+	assert.EqualError(t, err, "calc operation for variable 'a': operator abs expects 1 operands, got 2")
+
+	err = json.Unmarshal([]byte(`[{"type": "calc", "var": "a", "op": "+", "left": 1}]`), &operations)
+	assert.EqualError(t, err, "calc operation for variable 'a': operator + expects 2 operands, got 1")
+}
+
+func TestUpgradedCalculator_ExprOperations(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	var operations []Operation
+	err := json.Unmarshal([]byte(`[
+		{"type": "expr", "var": "z", "expr": "(x + 3) * y / 2"},
+		{"type": "print", "var": "z"},
+		{"type": "calc", "var": "x", "op": "+", "left": 1, "right": 4},
+		{"type": "expr", "var": "y", "expr": "-2 ** 2 + max(x, 10, 7) % 4"},
+		{"type": "expr", "var": "w", "expr": "y"},
+		{"type": "print", "var": "y"},
+		{"type": "print", "var": "w"}
+	]`), &operations)
+	assert.NoError(t, err)
+
+	calculator := NewUpgradedCalculator(logger, "expr_operations")
+	actualOutputs, err := calculator.Execute(context.Background(), operations)
+	assert.NoError(t, err)
+	assert.Equal(t, []PrintOutput{
+		{Var: "z", Value: NewIntValue(-8)},
+		{Var: "y", Value: NewIntValue(-2)},
+		{Var: "w", Value: NewIntValue(-2)},
+	}, actualOutputs)
+
+	err = json.Unmarshal([]byte(`[{"type": "expr", "var": "a", "expr": "(1 + 2"}]`), &operations)
+	assert.EqualError(t, err, "expression for variable 'a': column 7: expected ')', found end of expression")
+
+	err = json.Unmarshal([]byte(`[{"type": "expr", "var": "a", "expr": "abs(1, 2)"}]`), &operations)
+	assert.EqualError(t, err, "expression for variable 'a': column 1: operator abs expects 1 operands, got 2")
+
+	_, err = calculator.Execute(context.Background(), []Operation{
+		{Type: ExprOperation, Var: "a", Expr: "b + 1"},
+		{Type: ExprOperation, Var: "b", Expr: "a * 2"},
+	})
+	assert.ErrorContains(t, err, "dependency cycle detected")
+
+	calculator = NewUpgradedCalculator(logger, "min_int_literal")
+	actualOutputs, err = calculator.ExecuteWithOptions(context.Background(), []Operation{
+		{Type: ExprOperation, Var: "a", Expr: "-9223372036854775808"},
+		{Type: ExprOperation, Var: "b", Expr: "-(9223372036854775807) - 1 == a"},
+		{Type: PrintOperation, Var: "a"},
+		{Type: PrintOperation, Var: "b"},
+	}, Options{Overflow: CheckedOverflow})
+	assert.NoError(t, err)
+	assert.Equal(t, []PrintOutput{
+		{Var: "a", Value: NewIntValue(math.MinInt64)},
+		{Var: "b", Value: NewIntValue(1)},
+	}, actualOutputs)
+
+	calculator = NewUpgradedCalculator(slog.New(slog.NewTextHandler(io.Discard, nil)), "long_expression")
+	actualOutputs, err = calculator.Execute(context.Background(), []Operation{
+		{Type: ExprOperation, Var: "a", Expr: strings.Repeat("1 + ", 5000) + "1"},
+		{Type: PrintOperation, Var: "a"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []PrintOutput{{Var: "a", Value: NewIntValue(5001)}}, actualOutputs)
+}
+
+func TestUpgradedCalculator_Conditionals(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	var operations []Operation
+	err := json.Unmarshal([]byte(`[
+		{"type": "calc", "var": "x", "op": "-", "left": 3, "right": 3},
+		{"type": "expr", "var": "q", "expr": "x != 0 ? 10 / x : -1"},
+		{"type": "calc", "var": "positive", "op": ">", "left": "q", "right": 0},
+		{"type": "cond", "var": "r", "cond": "positive", "then": "q", "else": "fallback"},
+		{"type": "calc", "var": "fallback", "op": "*", "left": "q", "right": 100},
+		{"type": "print", "var": "q"},
+		{"type": "print", "var": "r"}
+	]`), &operations)
+	assert.NoError(t, err)
+
+	calculator := NewUpgradedCalculator(logger, "conditionals")
+	actualOutputs, err := calculator.Execute(context.Background(), operations)
+	assert.NoError(t, err)
+	assert.Equal(t, []PrintOutput{
+		{Var: "q", Value: NewIntValue(-1)},
+		{Var: "r", Value: NewIntValue(-100)},
+	}, actualOutputs)
+
+	calculator = NewUpgradedCalculator(logger, "unprinted_conditional")
+	result, err := calculator.Run(context.Background(), operations[:2], Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Computed: 2, Skipped: []string{}}, result.Summary)
+
+	err = json.Unmarshal([]byte(`[{"type": "cond", "var": "a", "cond": 1, "then": 2}]`), &operations)
+	assert.EqualError(t, err, "cond operation for variable 'a' requires cond, then and else operands")
+
+	_, err = calculator.Execute(context.Background(), []Operation{
+		{Type: ExprOperation, Var: "a", Expr: "1 > 0 ? 1 : b"},
+		{Type: ExprOperation, Var: "b", Expr: "a + 1"},
+	})
+	assert.EqualError(t, err, "dependency cycle detected: a -> b -> a")
+}
+
+func TestUpgradedCalculator_LazyEvaluation(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	var operations []Operation
+	err := json.Unmarshal([]byte(`[
+		{"type": "calc", "var": "x", "op": "+", "left": 1, "right": 2},
+		{"type": "calc", "var": "unused", "op": "/", "left": "x", "right": 0},
+		{"type": "expr", "var": "y", "expr": "x * 2 + 1"},
+		{"type": "expr", "var": "other", "expr": "unused - 1"},
+		{"type": "print", "var": "y"}
+	]`), &operations)
+	assert.NoError(t, err)
+
+	calculator := NewUpgradedCalculator(logger, "eager_evaluation")
+	_, err = calculator.Run(context.Background(), operations, Options{})
+	assert.EqualError(t, err, "division by zero")
+
+	calculator = NewUpgradedCalculator(logger, "lazy_evaluation")
+	result, err := calculator.Run(context.Background(), operations, Options{Evaluation: LazyEvaluation})
+	assert.NoError(t, err)
+	assert.Equal(t, Result{
+		Items:   []PrintOutput{{Var: "y", Value: NewIntValue(7)}},
+		Summary: Summary{Computed: 2, Skipped: []string{"unused", "other"}},
+	}, result)
+}
+
+func TestUpgradedCalculator_CollectErrors(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	var operations []Operation
+	err := json.Unmarshal([]byte(`[
+		{"type": "calc", "var": "a", "op": "/", "left": 10, "right": 0},
+		{"type": "calc", "var": "b", "op": "+", "left": "a", "right": 1},
+		{"type": "calc", "var": "c", "op": "*", "left": 5, "right": 2},
+		{"type": "expr", "var": "d", "expr": "c / (c - 10) + 1"},
+		{"type": "print", "var": "b"},
+		{"type": "print", "var": "c"},
+		{"type": "print", "var": "d"},
+		{"type": "expr", "var": "e", "expr": "a > 0 ? 1 : c"},
+		{"type": "print", "var": "e"}
+	]`), &operations)
+	assert.NoError(t, err)
+
+	for _, workers := range []string{"1", "4"} {
+		t.Run("workers "+workers, func(t *testing.T) {
+			t.Setenv("CALCULATOR_WORKERS", workers)
+			calculator := NewUpgradedCalculator(logger, "collect_errors")
+			result, err := calculator.Run(context.Background(), operations, Options{Errors: CollectErrors})
+			assert.NoError(t, err)
+			assert.Equal(t, []PrintOutput{{Var: "c", Value: NewIntValue(10)}}, result.Items)
+			assert.Equal(t, Summary{Computed: 1, Skipped: []string{}}, result.Summary)
+			assert.Equal(t, []OperationError{
+				{Index: 0, Var: "a", Code: DivisionByZeroCode, Message: "division by zero"},
+				{Index: 1, Var: "b", Code: UpstreamFailedCode, Message: "depends on failed variable 'a'"},
+				{Index: 3, Var: "d", Code: DivisionByZeroCode, Message: "division by zero"},
+				{Index: 4, Var: "b", Code: UpstreamFailedCode, Message: "depends on failed variable 'a'"},
+				{Index: 6, Var: "d", Code: UpstreamFailedCode, Message: "depends on failed variable 'd'"},
+				{Index: 7, Var: "e", Code: UpstreamFailedCode, Message: "depends on failed variable 'a'"},
+				{Index: 8, Var: "e", Code: UpstreamFailedCode, Message: "depends on failed variable 'a'"},
+			}, result.Errors)
+		})
+	}
+
+	calculator := NewUpgradedCalculator(logger, "fail_fast")
+	_, err = calculator.Run(context.Background(), operations, Options{})
+	assert.EqualError(t, err, "division by zero")
+}
+
+func TestUpgradedCalculator_Observe(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	var operations []Operation
+	err := json.Unmarshal([]byte(`[
+		{"type": "calc", "var": "x", "op": "+", "left": 1, "right": 2},
+		{"type": "print", "var": "x"},
+		{"type": "expr", "var": "y", "expr": "x / (x - 3)"},
+		{"type": "print", "var": "y"},
+		{"type": "expr", "var": "z", "expr": "x * 10"},
+		{"type": "print", "var": "z"}
+	]`), &operations)
+	assert.NoError(t, err)
+
+	t.Setenv("CALCULATOR_WORKERS", "4")
+	calculator := NewUpgradedCalculator(logger, "observe")
+	var (
+		prints []PrintOutput
+		errs   []OperationError
+	)
+	calculator.Observe(func(event Event) {
+		if event.Print != nil {
+			prints = append(prints, *event.Print)
+		}
+		if event.Error != nil {
+			errs = append(errs, *event.Error)
+		}
+	})
+	result, err := calculator.Run(context.Background(), operations, Options{
+		PrintOrder: CompletionPrintOrder,
+		Errors:     CollectErrors,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, result.Items, prints)
+	assert.ElementsMatch(t, result.Errors, errs)
+	assert.Len(t, errs, 2)
+}
+
+func TestUpgradedCalculator_Inputs(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	var request Request
+	err := json.Unmarshal([]byte(`{
+		"operations": [
+			{"type": "expr", "var": "total", "expr": "price * count"},
+			{"type": "print", "var": "total"}
+		],
+		"numbers": "decimal",
+		"inputs": {"price": "2.50", "count": 4}
+	}`), &request)
+	require.NoError(t, err)
+
+	calculator := NewUpgradedCalculator(logger, "inputs")
+	result, err := calculator.Run(context.Background(), request.Operations, request.Options)
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, "10.00", result.Items[0].Value.String())
+	assert.Equal(t, Summary{Computed: 1, Skipped: []string{}}, result.Summary)
+	assert.Equal(t, []string{"total"}, slices.Collect(maps.Keys(calculator.Variables())))
+
+	request.Options = Options{Inputs: map[string]Operand{"total": {IntValue: int64Ptr(1)}}}
+	_, err = NewUpgradedCalculator(logger, "collision").Run(context.Background(), request.Operations, request.Options)
+	var coded *Error
+	require.ErrorAs(t, err, &coded)
+	assert.Equal(t, DuplicateAssignmentCode, coded.Code)
+	assert.Equal(t, 0, *coded.Index)
+
+	request.Options = Options{Inputs: map[string]Operand{"price": {StringValue: stringPtr("other")}}}
+	_, err = NewUpgradedCalculator(logger, "variable_input").Run(context.Background(), request.Operations, request.Options)
+	require.ErrorAs(t, err, &coded)
+	assert.Equal(t, InvalidRequestCode, coded.Code)
+	assert.Equal(t, "inputs.price", coded.Field)
+
+	request.Options = Options{Inputs: map[string]Operand{"price": {BigValue: new(big.Int).Lsh(big.NewInt(1), 70)}}}
+	_, err = NewUpgradedCalculator(logger, "big_input").Run(context.Background(), request.Operations, request.Options)
+	require.ErrorAs(t, err, &coded)
+	assert.Equal(t, InvalidOperandCode, coded.Code)
+	assert.Equal(t, "inputs.price", coded.Field)
+}
@@ -0,0 +1,68 @@
+package common
+
+import "fmt"
+
+// Comparison operators. Their result is 1 when the comparison holds and 0
+// otherwise, so it can be used as a condition or in further arithmetic.
+const (
+	Eq CalcAvailableOperation = "=="
+	Ne CalcAvailableOperation = "!="
+	Lt CalcAvailableOperation = "<"
+	Le CalcAvailableOperation = "<="
+	Gt CalcAvailableOperation = ">"
+	Ge CalcAvailableOperation = ">="
+)
+
+func comparisonOperators() []Operator {
+	return []Operator{
+		comparisonOperator(Eq, "equality", func(cmp int) bool { return cmp == 0 }),
+		comparisonOperator(Ne, "inequality", func(cmp int) bool { return cmp != 0 }),
+		comparisonOperator(Lt, "less than", func(cmp int) bool { return cmp < 0 }),
+		comparisonOperator(Le, "less than or equal", func(cmp int) bool { return cmp <= 0 }),
+		comparisonOperator(Gt, "greater than", func(cmp int) bool { return cmp > 0 }),
+		comparisonOperator(Ge, "greater than or equal", func(cmp int) bool { return cmp >= 0 }),
+	}
+}
+
+func comparisonOperator(symbol CalcAvailableOperation, description string, holds func(cmp int) bool) Operator {
+	return Operator{
+		Symbol:      symbol,
+		Arity:       2,
+		Description: fmt.Sprintf("%s, 1 if it holds and 0 otherwise", description),
+		Kinds:       NumericKinds,
+		Apply: func(_ EvalContext, args []Value) (Value, error) {
+			if holds(compareValues(args[0], args[1])) {
+				return NewIntValue(1), nil
+			}
+			return NewIntValue(0), nil
+		},
+	}
+}
+
+// compareValues compares two values of the same kind, returning -1, 0 or 1.
+func compareValues(left, right Value) int {
+	switch left.Kind() {
+	case IntKind:
+		l, _ := left.Int64()
+		r, _ := right.Int64()
+		switch {
+		case l < r:
+			return -1
+		case l > r:
+			return 1
+		}
+		return 0
+	case BigKind:
+		return left.Big().Cmp(right.Big())
+	case DecimalKind:
+		return left.Decimal().Cmp(right.Decimal())
+	}
+	l, r := left.Float64(), right.Float64()
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	}
+	return 0
+}
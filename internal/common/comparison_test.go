@@ -0,0 +1,36 @@
+package common
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyOperator_Comparison(t *testing.T) {
+	huge, _ := new(big.Int).SetString("100000000000000000000", 10)
+	half, _ := ParseDecimal("0.5")
+	tests := []struct {
+		name  string
+		op    CalcAvailableOperation
+		left  Value
+		right Value
+		want  int64
+	}{
+		{"int equal", Eq, NewIntValue(3), NewIntValue(3), 1},
+		{"int not equal", Ne, NewIntValue(3), NewIntValue(3), 0},
+		{"int less", Lt, NewIntValue(-4), NewIntValue(3), 1},
+		{"int less or equal", Le, NewIntValue(4), NewIntValue(3), 0},
+		{"big greater", Gt, NewBigValue(huge), NewIntValue(3), 1},
+		{"decimal greater or equal", Ge, NewDecimalValue(half), NewIntValue(1), 0},
+		{"decimal scales", Eq, NewDecimalValue(half), NewDecimalValue(half.Rescale(4, DownRounding)), 1},
+		{"float and int", Lt, NewFloatValue(2.5), NewIntValue(3), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyOperator(EvalContext{Var: "x"}, tt.op, tt.left, tt.right)
+			assert.NoError(t, err)
+			assert.Equal(t, NewIntValue(tt.want), got)
+		})
+	}
+}
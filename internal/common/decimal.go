@@ -0,0 +1,226 @@
+package common
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultDecimalScale is the number of fractional digits kept in decimal
+// results when the request does not configure it.
+const DefaultDecimalScale = 2
+
+// MaxDecimalScale bounds the configurable scale of decimal results.
+const MaxDecimalScale = 64
+
+// maxDecimalDigits bounds the number of digits a decimal literal expands to
+// through its exponent, so that "1e2000000000" is rejected instead of
+// computed. It bounds the fractional digits of "1e-2000000000" likewise, the
+// tighter MaxDecimalScale applies to literals used as decimals only.
+const maxDecimalDigits = 4096
+
+type RoundingMode string
+
+const (
+	// HalfUpRounding rounds to the nearest neighbour, ties away from zero. It is the default mode.
+	HalfUpRounding RoundingMode = "half_up"
+	// HalfEvenRounding rounds to the nearest neighbour, ties to the even one.
+	HalfEvenRounding RoundingMode = "half_even"
+	// DownRounding truncates towards zero.
+	DownRounding RoundingMode = "down"
+	// UpRounding rounds away from zero.
+	UpRounding RoundingMode = "up"
+	// FloorRounding rounds towards negative infinity.
+	FloorRounding RoundingMode = "floor"
+	// CeilingRounding rounds towards positive infinity.
+	CeilingRounding RoundingMode = "ceiling"
+)
+
+func (mode *RoundingMode) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if !RoundingMode(s).valid() {
+		return NewError(InvalidRequestCode, "invalid rounding mode").WithField("decimal_rounding")
+	}
+	*mode = RoundingMode(s)
+	return nil
+}
+
+func (mode RoundingMode) valid() bool {
+	switch mode {
+	case "", HalfUpRounding, HalfEvenRounding, DownRounding, UpRounding, FloorRounding, CeilingRounding:
+		return true
+	}
+	return false
+}
+
+// Decimal is a fixed-point number equal to unscaled * 10^-scale.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+// ParseDecimal parses a decimal literal such as "-12.50" or "1.5e3".
+func ParseDecimal(s string) (Decimal, error) {
+	mantissa, exponent := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		mantissa = s[:i]
+		if exponent, err = strconv.ParseInt(s[i+1:], 10, 32); err != nil {
+			return Decimal{}, NewError(InvalidOperandCode, "invalid decimal '%s'", s)
+		}
+	}
+
+	digits, scale := mantissa, int64(0)
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		digits = mantissa[:i] + mantissa[i+1:]
+		scale = int64(len(mantissa) - i - 1)
+	}
+	unscaled, ok := new(big.Int).SetString(digits, 10)
+	if !ok || strings.ContainsAny(digits, "_") {
+		return Decimal{}, NewError(InvalidOperandCode, "invalid decimal '%s'", s)
+	}
+
+	scale -= exponent
+	if (scale < 0 && int64(len(digits))-scale > maxDecimalDigits) || scale > maxDecimalDigits {
+		return Decimal{}, NewError(InvalidOperandCode, "decimal '%s' has more than %d digits", s, maxDecimalDigits)
+	}
+	if scale < 0 {
+		unscaled.Mul(unscaled, pow10(int32(-scale)))
+		scale = 0
+	}
+	return Decimal{unscaled: unscaled, scale: int32(scale)}, nil
+}
+
+// NewDecimalFromBig returns the integer v as a decimal with scale 0.
+func NewDecimalFromBig(v *big.Int) Decimal {
+	return Decimal{unscaled: new(big.Int).Set(v), scale: 0}
+}
+
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+func (d Decimal) String() string {
+	s := new(big.Int).Abs(d.unscaled).String()
+	if d.scale > 0 {
+		if len(s) <= int(d.scale) {
+			s = strings.Repeat("0", int(d.scale)-len(s)+1) + s
+		}
+		s = s[:len(s)-int(d.scale)] + "." + s[len(s)-int(d.scale):]
+	}
+	if d.unscaled.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// Rescale returns d with exactly scale fractional digits, rounding if digits
+// are dropped.
+func (d Decimal) Rescale(scale int32, mode RoundingMode) Decimal {
+	switch {
+	case scale == d.scale:
+		return d
+	case scale > d.scale:
+		return Decimal{unscaled: new(big.Int).Mul(d.unscaled, pow10(scale-d.scale)), scale: scale}
+	default:
+		return Decimal{unscaled: divRound(d.unscaled, pow10(d.scale-scale), mode), scale: scale}
+	}
+}
+
+func (d Decimal) Add(o Decimal) Decimal {
+	l, r := align(d, o)
+	return Decimal{unscaled: new(big.Int).Add(l.unscaled, r.unscaled), scale: l.scale}
+}
+
+func (d Decimal) Sub(o Decimal) Decimal {
+	l, r := align(d, o)
+	return Decimal{unscaled: new(big.Int).Sub(l.unscaled, r.unscaled), scale: l.scale}
+}
+
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.unscaled, o.unscaled), scale: d.scale + o.scale}
+}
+
+// Quo divides d by o and rounds the quotient to scale fractional digits.
+func (d Decimal) Quo(o Decimal, scale int32, mode RoundingMode) (Decimal, error) {
+	if o.unscaled.Sign() == 0 {
+		return Decimal{}, errDivisionByZero
+	}
+	num := new(big.Int).Set(d.unscaled)
+	den := new(big.Int).Set(o.unscaled)
+	// d/o = (num / den) * 10^(o.scale - d.scale), shift it to the target scale.
+	if shift := scale + o.scale - d.scale; shift >= 0 {
+		num.Mul(num, pow10(shift))
+	} else {
+		den.Mul(den, pow10(-shift))
+	}
+	return Decimal{unscaled: divRound(num, den, mode), scale: scale}, nil
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.unscaled), scale: d.scale}
+}
+
+func (d Decimal) Sign() int {
+	return d.unscaled.Sign()
+}
+
+// Cmp compares d and o by value regardless of their scales.
+func (d Decimal) Cmp(o Decimal) int {
+	l, r := align(d, o)
+	return l.unscaled.Cmp(r.unscaled)
+}
+
+func align(l, r Decimal) (Decimal, Decimal) {
+	if l.scale < r.scale {
+		return l.Rescale(r.scale, DownRounding), r
+	}
+	return l, r.Rescale(l.scale, DownRounding)
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// divRound returns num / den rounded according to the mode.
+func divRound(num, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	sign := num.Sign() * den.Sign()
+	var increment bool
+	switch mode {
+	case DownRounding:
+	case UpRounding:
+		increment = true
+	case FloorRounding:
+		increment = sign < 0
+	case CeilingRounding:
+		increment = sign > 0
+	default:
+		half := new(big.Int).Abs(r)
+		half.Lsh(half, 1)
+		switch cmp := half.Cmp(new(big.Int).Abs(den)); {
+		case cmp > 0:
+			increment = true
+		case cmp == 0:
+			increment = mode != HalfEvenRounding || q.Bit(0) == 1
+		}
+	}
+
+	if increment {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+	return q
+}
@@ -0,0 +1,82 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1.5", "1.5"},
+		{"-0.05", "-0.05"},
+		{".25", "0.25"},
+		{"12", "12"},
+		{"1.5e3", "1500"},
+		{"25e-4", "0.0025"},
+	}
+
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.input)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, d.String())
+	}
+
+	_, err := ParseDecimal("1.2.3")
+	assert.Error(t, err)
+
+	for _, input := range []string{"1e2000000000", "1e20000000", "1.5e4096", "1e-2000000000", "1e-5000"} {
+		_, err = ParseDecimal(input)
+		assert.Equal(t, InvalidOperandCode, CodeOf(err), input)
+	}
+	d, err := ParseDecimal("1e4000")
+	assert.NoError(t, err)
+	assert.Len(t, d.String(), 4001)
+	d, err = ParseDecimal("1e-70")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(70), d.Scale())
+}
+
+func TestDecimal_Rescale(t *testing.T) {
+	tests := []struct {
+		input    string
+		mode     RoundingMode
+		expected string
+	}{
+		{"2.345", HalfUpRounding, "2.35"},
+		{"-2.345", HalfUpRounding, "-2.35"},
+		{"2.345", HalfEvenRounding, "2.34"},
+		{"2.355", HalfEvenRounding, "2.36"},
+		{"2.349", DownRounding, "2.34"},
+		{"2.341", UpRounding, "2.35"},
+		{"-2.341", FloorRounding, "-2.35"},
+		{"-2.349", CeilingRounding, "-2.34"},
+		{"2.3", HalfUpRounding, "2.30"},
+	}
+
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.input)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, d.Rescale(2, tt.mode).String(), "%s %s", tt.input, tt.mode)
+	}
+}
+
+func TestDecimal_Quo(t *testing.T) {
+	one, _ := ParseDecimal("1")
+	three, _ := ParseDecimal("3.0")
+
+	res, err := one.Quo(three, 4, HalfUpRounding)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.3333", res.String())
+
+	res, err = three.Quo(one, 0, HalfUpRounding)
+	assert.NoError(t, err)
+	assert.Equal(t, "3", res.String())
+
+	zero, _ := ParseDecimal("0.00")
+	_, err = one.Quo(zero, 2, HalfUpRounding)
+	assert.Error(t, err)
+}
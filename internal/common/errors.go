@@ -0,0 +1,210 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type ErrorMode string
+
+const (
+	// FailFastErrors stops the execution at the first failed operation and
+	// fails the whole request. It is the default mode.
+	FailFastErrors ErrorMode = "fail_fast"
+	// CollectErrors keeps computing the operations that do not depend on a
+	// failed one and reports every failure in Result.Errors.
+	CollectErrors ErrorMode = "collect"
+)
+
+func (mode *ErrorMode) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	switch ErrorMode(s) {
+	case "", FailFastErrors, CollectErrors:
+		*mode = ErrorMode(s)
+		return nil
+	default:
+		return NewError(InvalidRequestCode, "invalid errors mode").WithField("errors")
+	}
+}
+
+// ErrorCode classifies an error. Codes are stable and shared by the HTTP
+// and gRPC interfaces, clients are expected to match on them rather than on
+// messages.
+type ErrorCode string
+
+const (
+	// InvalidRequestCode reports a malformed request or invalid options.
+	InvalidRequestCode ErrorCode = "INVALID_REQUEST"
+	// InvalidOperationCode reports an unknown operation type or operator or
+	// a wrong number of operands.
+	InvalidOperationCode ErrorCode = "INVALID_OPERATION"
+	// InvalidOperandCode reports an operand that cannot be parsed or is
+	// outside the domain of the operator, such as a negative exponent.
+	InvalidOperandCode      ErrorCode = "INVALID_OPERAND"
+	SyntaxErrorCode         ErrorCode = "SYNTAX_ERROR"
+	UndefinedVariableCode   ErrorCode = "UNDEFINED_VARIABLE"
+	DuplicateAssignmentCode ErrorCode = "DUPLICATE_ASSIGNMENT"
+	CycleCode               ErrorCode = "CYCLE"
+	DivisionByZeroCode      ErrorCode = "DIVISION_BY_ZERO"
+	OverflowCode            ErrorCode = "OVERFLOW"
+	// UpstreamFailedCode marks operations that were not computed because a
+	// variable they depend on failed.
+	UpstreamFailedCode ErrorCode = "UPSTREAM_FAILED"
+	// EvaluationCode reports any other failure of an operator.
+	EvaluationCode ErrorCode = "EVALUATION_ERROR"
+	TimeoutCode    ErrorCode = "TIMEOUT"
+	CancelledCode  ErrorCode = "CANCELLED"
+	// NotFoundCode reports a session that does not exist or has expired.
+	NotFoundCode ErrorCode = "NOT_FOUND"
+	// LimitExceededCode reports a request that would exceed a configured
+	// limit, such as the number of sessions.
+	LimitExceededCode ErrorCode = "LIMIT_EXCEEDED"
+	InternalCode      ErrorCode = "INTERNAL"
+)
+
+// Error is an error with a stable code. Index and Var locate the operation
+// of the request that caused it, Field names the field at fault, such as
+// "left" or "print_order", when it is known.
+type Error struct {
+	Code    ErrorCode
+	Message string
+	Index   *int
+	Var     string
+	Field   string
+	Err     error
+}
+
+// NewError formats the message like fmt.Errorf, including %w wrapping.
+func NewError(code ErrorCode, format string, args ...any) *Error {
+	err := fmt.Errorf(format, args...)
+	return &Error{Code: code, Message: err.Error(), Err: errors.Unwrap(err)}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) ErrorCode() ErrorCode {
+	return e.Code
+}
+
+// AtOperation returns a copy of the error located at the operation.
+func (e *Error) AtOperation(index int, name string) *Error {
+	located := *e
+	located.Index = &index
+	located.Var = name
+	return &located
+}
+
+// WithField returns a copy of the error naming the field at fault.
+func (e *Error) WithField(field string) *Error {
+	located := *e
+	located.Field = field
+	return &located
+}
+
+// CodeOf returns the code of the error. Errors without a code are
+// classified as INTERNAL, context errors as TIMEOUT and CANCELLED.
+func CodeOf(err error) ErrorCode {
+	var coded interface{ ErrorCode() ErrorCode }
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &coded):
+		return coded.ErrorCode()
+	case errors.Is(err, context.DeadlineExceeded):
+		return TimeoutCode
+	case errors.Is(err, context.Canceled):
+		return CancelledCode
+	}
+	return InternalCode
+}
+
+// atOperation locates an error reported while computing the operation at
+// index. Errors of operators without a code are classified as
+// EVALUATION_ERROR.
+func atOperation(err error, index int, name string) error {
+	var located *Error
+	if errors.As(err, &located) && located.Index != nil {
+		return err
+	}
+	code := CodeOf(err)
+	if code == InternalCode {
+		code = EvaluationCode
+	}
+	result := &Error{Code: code, Message: err.Error(), Index: &index, Var: name, Err: err}
+	if located != nil {
+		result.Field = located.Field
+	}
+	return result
+}
+
+// relocate translates the operation an error is located at from the
+// expanded program to the request.
+func relocate(err error, origins []int, requested []Operation) error {
+	var located *Error
+	if !errors.As(err, &located) || located.Index == nil {
+		return err
+	}
+	origin := origins[*located.Index]
+	relocated := located.AtOperation(origin, located.Var)
+	if temporaryVariable(located.Var) || located.Var == "" {
+		relocated.Var = requested[origin].Var
+	}
+	return relocated
+}
+
+// OperationError describes a failed operation. Index is the position of the
+// operation in the request, operations compiled from an expression report
+// the index and the variable of the expr operation.
+type OperationError struct {
+	Index   int       `json:"index"`
+	Var     string    `json:"var"`
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// upstreamError fails the operations depending on a failed one. source is
+// the index of the operation that failed first.
+type upstreamError struct {
+	source int
+}
+
+func (e *upstreamError) Error() string {
+	return fmt.Sprintf("operation %d failed", e.source)
+}
+
+// sourceOf returns the index of the operation the failure of the operation
+// at index originates from.
+func sourceOf(index int, err error) int {
+	var upstream *upstreamError
+	if errors.As(err, &upstream) {
+		return upstream.source
+	}
+	return index
+}
+
+func (e *upstreamError) ErrorCode() ErrorCode {
+	return UpstreamFailedCode
+}
+
+// newOperationError describes the failure of the requested operation at
+// index. variable names the variable of a requested operation by the
+// index of an expanded one, to explain upstream failures.
+func newOperationError(index int, name string, err error, variable func(source int) string) OperationError {
+	message := err.Error()
+	var upstream *upstreamError
+	if errors.As(err, &upstream) {
+		message = fmt.Sprintf("depends on failed variable '%s'", variable(upstream.source))
+	}
+	return OperationError{Index: index, Var: name, Code: CodeOf(err), Message: message}
+}
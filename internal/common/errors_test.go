@@ -0,0 +1,123 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCodes(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	tests := []struct {
+		name    string
+		program string
+		code    ErrorCode
+		index   int
+		varName string
+		field   string
+	}{
+		{
+			name: "undefined variable",
+			program: `[
+				{"type": "expr", "var": "a", "expr": "1 + 2"},
+				{"type": "calc", "var": "b", "op": "+", "left": "a", "right": "c"}
+			]`,
+			code: UndefinedVariableCode, index: 1, varName: "b",
+		},
+		{
+			name: "duplicate assignment",
+			program: `[
+				{"type": "expr", "var": "a", "expr": "1 + 2 * 3"},
+				{"type": "calc", "var": "a", "op": "+", "left": 1, "right": 2}
+			]`,
+			code: DuplicateAssignmentCode, index: 1, varName: "a",
+		},
+		{
+			name: "cycle",
+			program: `[
+				{"type": "calc", "var": "a", "op": "+", "left": "b", "right": 1},
+				{"type": "calc", "var": "b", "op": "+", "left": "a", "right": 1}
+			]`,
+			code: CycleCode, index: 0, varName: "a",
+		},
+		{
+			name: "division by zero in expression",
+			program: `[
+				{"type": "calc", "var": "a", "op": "+", "left": 1, "right": 2},
+				{"type": "expr", "var": "b", "expr": "a / (a - 3) + 1"}
+			]`,
+			code: DivisionByZeroCode, index: 1, varName: "b",
+		},
+		{
+			name: "overflow",
+			program: `[
+				{"type": "calc", "var": "a", "op": "*", "left": 9223372036854775807, "right": 2}
+			]`,
+			code: OverflowCode, index: 0, varName: "a",
+		},
+		{
+			name: "invalid operand",
+			program: `[
+				{"type": "calc", "var": "a", "op": "<<", "left": 1.5, "right": 2}
+			]`,
+			code: InvalidOperandCode, index: 0, varName: "a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var operations []Operation
+			assert.NoError(t, json.Unmarshal([]byte(tt.program), &operations))
+
+			calculator := NewUpgradedCalculator(logger, "error_codes")
+			_, err := calculator.Run(context.Background(), operations, Options{Overflow: CheckedOverflow})
+			assert.Equal(t, tt.code, CodeOf(err))
+
+			var located *Error
+			if assert.True(t, errors.As(err, &located)) && assert.NotNil(t, located.Index) {
+				assert.Equal(t, tt.index, *located.Index)
+				assert.Equal(t, tt.varName, located.Var)
+				assert.Equal(t, tt.field, located.Field)
+			}
+		})
+	}
+}
+
+func TestErrorCodes_Decoding(t *testing.T) {
+	var req Request
+	err := json.Unmarshal([]byte(`{"operations": [], "print_order": "random"}`), &req)
+	assert.Equal(t, InvalidRequestCode, CodeOf(err))
+
+	err = json.Unmarshal([]byte(`[{"type": "calc", "var": "a", "op": "+", "left": "1$", "right": 1}]`), &req)
+	assert.Equal(t, InvalidOperandCode, CodeOf(err))
+
+	err = json.Unmarshal([]byte(`[{"type": "expr", "var": "a", "expr": "1 +"}]`), &req)
+	assert.Equal(t, SyntaxErrorCode, CodeOf(err))
+
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+	calculator := NewUpgradedCalculator(logger, "error_codes")
+	_, err = calculator.Execute(context.Background(), []Operation{
+		{Type: CalcOperation, Var: "a", Op: Add, Left: &Operand{IntValue: int64Ptr(1)}, Right: &Operand{IntValue: int64Ptr(2)}},
+		{Type: ExprOperation, Var: "b", Expr: "avgg(a, 1)"},
+	})
+	var located *Error
+	if assert.True(t, errors.As(err, &located)) {
+		assert.Equal(t, InvalidOperationCode, located.Code)
+		assert.Equal(t, 1, *located.Index)
+		assert.Equal(t, "b", located.Var)
+		assert.Equal(t, "expr", located.Field)
+	}
+
+	assert.Equal(t, TimeoutCode, CodeOf(context.DeadlineExceeded))
+	assert.Equal(t, InternalCode, CodeOf(errors.New("unexpected")))
+	assert.True(t, errors.Is(NewError(EvaluationCode, "wrapped: %w", errDivisionByZero), errDivisionByZero))
+}
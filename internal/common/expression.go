@@ -0,0 +1,225 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"upgraded-calculator/internal/expr"
+)
+
+// ExpressionError reports an invalid expression of an expr operation.
+// Offset is the byte offset in the expression the problem was found at.
+type ExpressionError struct {
+	Var    string
+	Offset int
+	Msg    string
+	// code is SYNTAX_ERROR unless set.
+	code ErrorCode
+}
+
+func (e *ExpressionError) ErrorCode() ErrorCode {
+	if e.code == "" {
+		return SyntaxErrorCode
+	}
+	return e.code
+}
+
+func (e *ExpressionError) Error() string {
+	return fmt.Sprintf("expression for variable '%s': column %d: %s", e.Var, e.Offset+1, e.Msg)
+}
+
+// expandExpressions replaces every expr operation with the calc operations
+// computing it, so that expressions take part in dependency resolution
+// like any other calc operation. It rejects invalid variable names first,
+// see Operation.checkVar. It also returns, for every resulting
+// operation, the index of the requested operation it comes from.
+func expandExpressions(operations []Operation) ([]Operation, []int, error) {
+	result := make([]Operation, 0, len(operations))
+	origins := make([]int, 0, len(operations))
+	for i, op := range operations {
+		if err := op.checkVar(); err != nil {
+			return nil, nil, atOperation(err, i, op.Var)
+		}
+		if op.Type != ExprOperation {
+			result = append(result, op)
+			origins = append(origins, i)
+			continue
+		}
+
+		compiled, err := compileExpression(i, op)
+		if err != nil {
+			return nil, nil, &Error{Code: CodeOf(err), Message: err.Error(), Index: &i, Var: op.Var, Field: "expr", Err: err}
+		}
+		result = append(result, compiled...)
+		for range compiled {
+			origins = append(origins, i)
+		}
+	}
+	return result, origins, nil
+}
+
+// compileExpression turns the expression of the operation at the given
+// index into calc operations. Intermediate results are stored in variables
+// named "<var>$<index>.<n>", which cannot clash with user variables since
+// those consist of letters only. The last operation assigns the target.
+func compileExpression(index int, op Operation) ([]Operation, error) {
+	node, err := expr.Parse(op.Expr)
+	if err != nil {
+		var syntaxErr *expr.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, &ExpressionError{Var: op.Var, Offset: syntaxErr.Offset, Msg: syntaxErr.Msg}
+		}
+		return nil, err
+	}
+
+	c := &expressionCompiler{prefix: fmt.Sprintf("%s$%d", op.Var, index)}
+	operand, err := c.compile(node)
+	if err != nil {
+		var exprErr *ExpressionError
+		if errors.As(err, &exprErr) {
+			exprErr.Var = op.Var
+		}
+		return nil, err
+	}
+
+	if len(c.operations) == 0 || operand.StringValue == nil || *operand.StringValue != c.last() {
+		// The expression is a single literal or variable, copy it.
+		c.operations = append(c.operations, Operation{Type: CalcOperation, Op: Sum, Args: []Operand{*operand}})
+	}
+	c.operations[len(c.operations)-1].Var = op.Var
+	return c.operations, nil
+}
+
+// temporaryVariable reports whether the variable holds an intermediate
+// result of an expression.
+func temporaryVariable(name string) bool {
+	return strings.Contains(name, "$")
+}
+
+type expressionCompiler struct {
+	prefix     string
+	operations []Operation
+	// branches is the nesting depth of conditional branches being compiled.
+	// Operations emitted inside a branch are deferred, so that for example
+	// "x != 0 ? 10 / x : 0" never divides by zero.
+	branches int
+}
+
+func (c *expressionCompiler) last() string {
+	return c.operations[len(c.operations)-1].Var
+}
+
+// compile emits the operations computing the node and returns the operand
+// holding its value.
+func (c *expressionCompiler) compile(node expr.Node) (*Operand, error) {
+	switch n := node.(type) {
+	case *expr.Number:
+		operand, err := ParseOperand(n.Text)
+		if err != nil {
+			return nil, &ExpressionError{Offset: n.Pos, Msg: err.Error(), code: InvalidOperandCode}
+		}
+		return &operand, nil
+	case *expr.Ident:
+		name := n.Name
+		return &Operand{StringValue: &name}, nil
+	case *expr.Unary:
+		if n.Op == "+" {
+			return c.compile(n.X)
+		}
+		if number, ok := n.X.(*expr.Number); ok {
+			// A negated literal is a negative literal, so that the least
+			// int64 can be written.
+			return c.compile(&expr.Number{Text: "-" + number.Text, Pos: n.Pos})
+		}
+		return c.emit(n.Pos, Neg, n.X)
+	case *expr.Binary:
+		return c.emitChain(n)
+	case *expr.Call:
+		return c.emit(n.Pos, CalcAvailableOperation(n.Func), n.Args...)
+	case *expr.Conditional:
+		return c.emitConditional(n)
+	}
+	return nil, &ExpressionError{Offset: node.Offset(), Msg: fmt.Sprintf("unsupported expression node %T", node)}
+}
+
+func (c *expressionCompiler) emit(pos int, symbol CalcAvailableOperation, args ...expr.Node) (*Operand, error) {
+	operands := make([]Operand, 0, len(args))
+	for _, arg := range args {
+		operand, err := c.compile(arg)
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, *operand)
+	}
+	return c.emitOperands(pos, symbol, operands)
+}
+
+// emitChain compiles a binary node and the binary nodes down its left
+// operands iteratively, as a chain such as "1 + 2 + ... + n" nests as deep as
+// it is long.
+func (c *expressionCompiler) emitChain(n *expr.Binary) (*Operand, error) {
+	chain := []*expr.Binary{n}
+	for left, ok := n.X.(*expr.Binary); ok; left, ok = left.X.(*expr.Binary) {
+		chain = append(chain, left)
+	}
+	acc, err := c.compile(chain[len(chain)-1].X)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		right, err := c.compile(chain[i].Y)
+		if err != nil {
+			return nil, err
+		}
+		if acc, err = c.emitOperands(chain[i].Pos, CalcAvailableOperation(chain[i].Op), []Operand{*acc, *right}); err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
+
+func (c *expressionCompiler) emitOperands(pos int, symbol CalcAvailableOperation, operands []Operand) (*Operand, error) {
+	operation := Operation{Type: CalcOperation, Op: symbol, Args: operands}
+
+	operator, ok := LookupOperator(symbol)
+	if !ok {
+		return nil, &ExpressionError{Offset: pos, Msg: fmt.Sprintf("unknown operator '%s'", symbol), code: InvalidOperationCode}
+	}
+	if err := operator.CheckArity(len(operation.Args)); err != nil {
+		return nil, &ExpressionError{Offset: pos, Msg: err.Error(), code: InvalidOperationCode}
+	}
+
+	return c.append(operation), nil
+}
+
+func (c *expressionCompiler) emitConditional(n *expr.Conditional) (*Operand, error) {
+	cond, err := c.compile(n.Cond)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the operations of the branches are deferred, the cond operation
+	// itself runs whenever the expression does.
+	c.branches++
+	then, err := c.compile(n.Then)
+	if err != nil {
+		c.branches--
+		return nil, err
+	}
+	otherwise, err := c.compile(n.Else)
+	c.branches--
+	if err != nil {
+		return nil, err
+	}
+	return c.append(Operation{Type: CondOperation, Cond: cond, Then: then, Else: otherwise}), nil
+}
+
+// append names the operation after the next free temporary variable, adds
+// it to the result and returns the operand reading it.
+func (c *expressionCompiler) append(operation Operation) *Operand {
+	operation.Var = fmt.Sprintf("%s.%d", c.prefix, len(c.operations)+1)
+	operation.deferred = c.branches > 0
+	c.operations = append(c.operations, operation)
+	name := operation.Var
+	return &Operand{StringValue: &name}
+}
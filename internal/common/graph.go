@@ -0,0 +1,196 @@
+package common
+
+import (
+	"slices"
+	"strings"
+)
+
+// dependencyGraph is the static view of a program: which calc operation
+// assigns every variable, which variables every operation reads and which
+// operations have to wait for a given one.
+type dependencyGraph struct {
+	producers map[string]int
+	deps      [][]string
+	// branches holds the variables a cond operation reads only from the
+	// branch it takes. They are validated like deps but waited for at run
+	// time, once the condition is known.
+	branches   [][]string
+	dependents [][]int
+	deferred   []bool
+	prints     []bool
+}
+
+// ValidateProgram runs the static analysis Run starts with, without
+// computing anything, for a program that will run with the given variables
+// defined. Errors are located at the requested operations.
+func ValidateProgram(operations []Operation, defined []string) error {
+	requested := operations
+	operations, origins, err := expandExpressions(operations)
+	if err != nil {
+		return err
+	}
+	_, err = buildDependencyGraph(operations, func(name string) bool {
+		return slices.Contains(defined, name)
+	})
+	if err != nil {
+		return relocate(err, origins, requested)
+	}
+	return nil
+}
+
+// buildDependencyGraph validates the program before anything is executed.
+// It rejects duplicate assignments, unknown operators, wrong operand counts,
+// references to variables no calc operation assigns and dependency cycles, so that invalid programs fail
+// without starting a single worker. Variables for which defined reports true
+// are already computed: operations may read them but not assign them.
+func buildDependencyGraph(operations []Operation, defined func(name string) bool) (*dependencyGraph, error) {
+	graph := &dependencyGraph{
+		producers:  make(map[string]int),
+		deps:       make([][]string, len(operations)),
+		branches:   make([][]string, len(operations)),
+		dependents: make([][]int, len(operations)),
+		deferred:   make([]bool, len(operations)),
+		prints:     make([]bool, len(operations)),
+	}
+
+	for i, op := range operations {
+		graph.deferred[i] = op.deferred
+		graph.prints[i] = op.Type == PrintOperation
+		if !op.assigns() {
+			continue
+		}
+		if prev, exists := graph.producers[op.Var]; exists {
+			return nil, NewError(DuplicateAssignmentCode, "variable '%s' is assigned more than once (operations %d and %d)", op.Var, prev, i).AtOperation(i, op.Var)
+		}
+		if defined(op.Var) {
+			return nil, NewError(DuplicateAssignmentCode, "variable '%s' is already assigned", op.Var).AtOperation(i, op.Var)
+		}
+		graph.producers[op.Var] = i
+	}
+
+	for i, op := range operations {
+		switch op.Type {
+		case CalcOperation:
+			if err := op.CheckOperands(); err != nil {
+				return nil, atOperation(err, i, op.Var)
+			}
+			for _, operand := range op.Operands() {
+				if operand.StringValue != nil && !defined(*operand.StringValue) {
+					graph.deps[i] = append(graph.deps[i], *operand.StringValue)
+				}
+			}
+		case CondOperation:
+			if err := op.CheckOperands(); err != nil {
+				return nil, atOperation(err, i, op.Var)
+			}
+			if op.Cond.StringValue != nil && !defined(*op.Cond.StringValue) {
+				graph.deps[i] = []string{*op.Cond.StringValue}
+			}
+			for _, operand := range []*Operand{op.Then, op.Else} {
+				if operand.StringValue != nil && !defined(*operand.StringValue) {
+					graph.branches[i] = append(graph.branches[i], *operand.StringValue)
+				}
+			}
+		case PrintOperation:
+			if !defined(op.Var) {
+				graph.deps[i] = []string{op.Var}
+			}
+		}
+
+		for _, name := range graph.deps[i] {
+			producer, exists := graph.producers[name]
+			if !exists {
+				return nil, NewError(UndefinedVariableCode, "variable '%s' is undefined", name).AtOperation(i, op.Var)
+			}
+			graph.dependents[producer] = append(graph.dependents[producer], i)
+		}
+		for _, name := range graph.branches[i] {
+			if _, exists := graph.producers[name]; !exists {
+				return nil, NewError(UndefinedVariableCode, "variable '%s' is undefined", name).AtOperation(i, op.Var)
+			}
+		}
+	}
+
+	if cycle := graph.findCycle(operations); cycle != nil {
+		return nil, NewError(CycleCode, "dependency cycle detected: %s", strings.Join(cycle, " -> ")).AtOperation(graph.producers[cycle[0]], cycle[0])
+	}
+
+	return graph, nil
+}
+
+// pendingCounts returns, for every operation, the number of inputs that
+// still have to be computed before the operation can be dispatched.
+func (g *dependencyGraph) pendingCounts() []int {
+	pending := make([]int, len(g.deps))
+	for i, deps := range g.deps {
+		pending[i] = len(deps)
+	}
+	return pending
+}
+
+// roots returns the operations that are demanded up front: every operation
+// except deferred ones in the eager mode and only print operations in the
+// lazy mode. Other operations run only when something demands their result.
+func (g *dependencyGraph) roots(mode EvaluationMode) []int {
+	var roots []int
+	for i, deferred := range g.deferred {
+		if mode == LazyEvaluation {
+			if g.prints[i] {
+				roots = append(roots, i)
+			}
+		} else if !deferred {
+			roots = append(roots, i)
+		}
+	}
+	return roots
+}
+
+// findCycle returns the variables forming the first dependency cycle found,
+// with the starting variable repeated at the end, or nil for an acyclic graph.
+func (g *dependencyGraph) findCycle(operations []Operation) []string {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int, len(g.producers))
+	var path []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case done:
+			return nil
+		case inProgress:
+			for i, v := range path {
+				if v == name {
+					return append(append([]string{}, path[i:]...), name)
+				}
+			}
+		}
+
+		state[name] = inProgress
+		path = append(path, name)
+		producer := g.producers[name]
+		for _, deps := range [][]string{g.deps[producer], g.branches[producer]} {
+			for _, dep := range deps {
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		return nil
+	}
+
+	for _, op := range operations {
+		if !op.assigns() {
+			continue
+		}
+		if cycle := visit(op.Var); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
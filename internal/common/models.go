@@ -1,8 +1,10 @@
 package common
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"math/big"
 	"regexp"
 	"strconv"
 	"strings"
@@ -13,6 +15,12 @@ type OperationType string
 const (
 	CalcOperation  OperationType = "calc"
 	PrintOperation OperationType = "print"
+	// ExprOperation assigns an infix expression such as "(x + 3) * y / 2".
+	// It is compiled into calc operations before execution.
+	ExprOperation OperationType = "expr"
+	// CondOperation assigns Then if Cond is non-zero and Else otherwise. Only
+	// the variable of the chosen branch is waited for.
+	CondOperation OperationType = "cond"
 )
 
 func (opType *OperationType) UnmarshalJSON(b []byte) error {
@@ -21,14 +29,16 @@ func (opType *OperationType) UnmarshalJSON(b []byte) error {
 		return err
 	}
 	switch OperationType(s) {
-	case CalcOperation, PrintOperation:
+	case CalcOperation, PrintOperation, ExprOperation, CondOperation:
 		*opType = OperationType(s)
 		return nil
 	default:
-		return errors.New("invalid operation type")
+		return NewError(InvalidOperationCode, "invalid operation type").WithField("type")
 	}
 }
 
+// CalcAvailableOperation is the symbol of a registered Operator. The
+// constants below are the built-in operators.
 type CalcAvailableOperation string
 
 const (
@@ -36,6 +46,28 @@ const (
 	Sub CalcAvailableOperation = "-"
 	Mul CalcAvailableOperation = "*"
 	Div CalcAvailableOperation = "/"
+	// Mod is the remainder of the truncated division, it has the sign of the dividend.
+	Mod CalcAvailableOperation = "%"
+	// Pow raises the left operand to a non-negative integer power.
+	Pow CalcAvailableOperation = "**"
+	And CalcAvailableOperation = "&"
+	Or  CalcAvailableOperation = "|"
+	Xor CalcAvailableOperation = "^"
+	Shl CalcAvailableOperation = "<<"
+	Shr CalcAvailableOperation = ">>"
+	Min CalcAvailableOperation = "min"
+	Max CalcAvailableOperation = "max"
+	// FloorDiv divides rounding the quotient towards negative infinity.
+	FloorDiv CalcAvailableOperation = "floordiv"
+	// CeilDiv divides rounding the quotient towards positive infinity.
+	CeilDiv CalcAvailableOperation = "ceildiv"
+	Sum     CalcAvailableOperation = "sum"
+	Product CalcAvailableOperation = "product"
+	Neg     CalcAvailableOperation = "neg"
+	Abs     CalcAvailableOperation = "abs"
+	Sign    CalcAvailableOperation = "sign"
+	// Sqrt is the square root, rounded down for integers.
+	Sqrt CalcAvailableOperation = "sqrt"
 )
 
 func (opType *CalcAvailableOperation) UnmarshalJSON(b []byte) error {
@@ -43,43 +75,350 @@ func (opType *CalcAvailableOperation) UnmarshalJSON(b []byte) error {
 	if err := json.Unmarshal(b, &s); err != nil {
 		return err
 	}
-	switch CalcAvailableOperation(s) {
-	case Add, Sub, Mul, Div:
-		*opType = CalcAvailableOperation(s)
-		return nil
-	default:
-		return errors.New("calculator unavailable operation")
+	if _, ok := LookupOperator(CalcAvailableOperation(s)); !ok {
+		return NewError(InvalidOperationCode, "calculator unavailable operation").WithField("op")
 	}
+	*opType = CalcAvailableOperation(s)
+	return nil
 }
 
 type Operand struct {
 	IntValue    *int64
 	StringValue *string
+	// BigValue holds integer literals that do not fit into int64.
+	BigValue *big.Int
+	// DecimalValue holds fractional literals. They are decimals in the
+	// decimal numbers mode and float64 values otherwise.
+	DecimalValue *Decimal
+	// FloatValue holds literals explicitly typed as float64.
+	FloatValue *float64
+}
+
+var (
+	fractionalLiteral = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+	variableName      = regexp.MustCompile(`^[A-z]+$`)
+)
+
+// IsVariableName reports whether operands may refer to a variable by the name.
+// Intermediate results of expressions use names that are never valid.
+func IsVariableName(s string) bool {
+	return variableName.MatchString(s)
 }
 
 func (op *Operand) UnmarshalJSON(b []byte) error {
 	var s = string(b)
 	s = strings.ReplaceAll(s, "\"", "")
 
+	parsed, err := ParseOperand(s)
+	if err != nil {
+		return err
+	}
+	*op = parsed
+	return nil
+}
+
+// MarshalJSON writes the operand as it is written in requests: variables
+// and literals that do not fit into a JSON number as strings.
+func (op Operand) MarshalJSON() ([]byte, error) {
+	switch {
+	case op.StringValue != nil:
+		return json.Marshal(*op.StringValue)
+	case op.BigValue != nil:
+		return json.Marshal(op.BigValue.String())
+	case op.DecimalValue != nil:
+		// Without a point a decimal of scale 0 would read back as an integer.
+		s := op.DecimalValue.String()
+		if !strings.Contains(s, ".") {
+			s += "."
+		}
+		return json.Marshal(s)
+	case op.FloatValue != nil:
+		return json.Marshal(*op.FloatValue)
+	case op.IntValue != nil:
+		return json.Marshal(*op.IntValue)
+	}
+	return []byte("null"), nil
+}
+
+// ParseOperand parses a number literal or a variable name.
+func ParseOperand(s string) (Operand, error) {
 	if num, err := strconv.ParseInt(s, 10, 64); err == nil {
-		*op = Operand{IntValue: &num, StringValue: nil}
-		return nil
-	} else if m, err := regexp.MatchString("^[A-z]+$", s); err == nil && m {
-		*op = Operand{IntValue: nil, StringValue: &s}
-		return nil
+		return Operand{IntValue: &num, StringValue: nil}, nil
+	} else if errors.Is(err, strconv.ErrRange) {
+		num, _ := new(big.Int).SetString(s, 10)
+		return Operand{BigValue: num}, nil
+	} else if fractionalLiteral.MatchString(s) {
+		num, err := ParseDecimal(s)
+		if err != nil {
+			return Operand{}, err
+		}
+		return Operand{DecimalValue: &num}, nil
+	} else if IsVariableName(s) {
+		return Operand{IntValue: nil, StringValue: &s}, nil
 	}
-	return errors.New("invalid type of operand")
+	return Operand{}, NewError(InvalidOperandCode, "invalid type of operand")
 }
 
+// Operation is a single program step. Operands of a calc operation are given
+// either as Left and Right for binary operators or as Args for operators of
+// any arity. An expr operation carries its whole computation in Expr, a cond
+// operation chooses between Then and Else depending on Cond.
 type Operation struct {
 	Type  OperationType          `json:"type"`
 	Op    CalcAvailableOperation `json:"op,omitempty"`
 	Var   string                 `json:"var"`
 	Left  *Operand               `json:"left,omitempty"`
 	Right *Operand               `json:"right,omitempty"`
+	Args  []Operand              `json:"args,omitempty"`
+	Expr  string                 `json:"expr,omitempty"`
+	Cond  *Operand               `json:"cond,omitempty"`
+	Then  *Operand               `json:"then,omitempty"`
+	Else  *Operand               `json:"else,omitempty"`
+
+	// deferred operations compute a branch of a conditional expression and
+	// only run once the conditional asks for their result.
+	deferred bool
+}
+
+func (op *Operation) UnmarshalJSON(b []byte) error {
+	type plain Operation
+	var o plain
+	if err := json.Unmarshal(b, &o); err != nil {
+		return err
+	}
+	*op = Operation(o)
+	return op.CheckOperands()
+}
+
+// Operands returns the operands of a calc operation in order.
+func (op Operation) Operands() []*Operand {
+	if len(op.Args) > 0 {
+		operands := make([]*Operand, len(op.Args))
+		for i := range op.Args {
+			operands[i] = &op.Args[i]
+		}
+		return operands
+	}
+	var operands []*Operand
+	for _, operand := range []*Operand{op.Left, op.Right} {
+		if operand != nil {
+			operands = append(operands, operand)
+		}
+	}
+	return operands
+}
+
+// assigns reports whether the operation assigns its variable.
+func (op Operation) assigns() bool {
+	return op.Type == CalcOperation || op.Type == CondOperation
+}
+
+// checkVar validates the name of the variable the operation assigns or
+// prints. Names consist of letters only, like variable operands, so that
+// they cannot clash with the intermediate results of expressions.
+func (op Operation) checkVar() error {
+	switch op.Type {
+	case CalcOperation, CondOperation, ExprOperation, PrintOperation:
+		if !IsVariableName(op.Var) {
+			return NewError(InvalidOperationCode, "invalid variable name '%s'", op.Var).WithField("var")
+		}
+	}
+	return nil
+}
+
+// CheckOperands validates that a calc operation uses a registered operator
+// with the number of operands it expects, that an expr operation holds a
+// valid expression and that a cond operation has all three operands.
+func (op Operation) CheckOperands() error {
+	switch op.Type {
+	case ExprOperation:
+		_, err := compileExpression(0, op)
+		return err
+	case CondOperation:
+		if op.Cond == nil || op.Then == nil || op.Else == nil {
+			return NewError(InvalidOperandCode, "cond operation for variable '%s' requires cond, then and else operands", op.Var).WithField("cond")
+		}
+		return nil
+	case CalcOperation:
+	default:
+		return nil
+	}
+	if len(op.Args) > 0 && (op.Left != nil || op.Right != nil) {
+		return NewError(InvalidOperationCode, "calc operation for variable '%s' cannot have both args and left/right operands", op.Var).WithField("args")
+	}
+	operator, ok := LookupOperator(op.Op)
+	if !ok {
+		return NewError(InvalidOperationCode, "invalid operation").WithField("op")
+	}
+	if err := operator.CheckArity(len(op.Operands())); err != nil {
+		return NewError(InvalidOperationCode, "calc operation for variable '%s': %w", op.Var, err)
+	}
+	return nil
 }
 
 type PrintOutput struct {
 	Var   string `json:"var"`
-	Value int64  `json:"value"`
+	Value Value  `json:"value"`
+	Seq   *int64 `json:"seq,omitempty"`
+}
+
+type PrintOrder string
+
+const (
+	// RequestPrintOrder lists prints in the order print operations appear in the request.
+	RequestPrintOrder PrintOrder = "request"
+	// CompletionPrintOrder lists prints in the order they were resolved and numbers them.
+	CompletionPrintOrder PrintOrder = "completion"
+)
+
+func (order *PrintOrder) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	switch PrintOrder(s) {
+	case "", RequestPrintOrder, CompletionPrintOrder:
+		*order = PrintOrder(s)
+		return nil
+	default:
+		return NewError(InvalidRequestCode, "invalid print order").WithField("print_order")
+	}
+}
+
+type EvaluationMode string
+
+const (
+	// EagerEvaluation computes every calc operation. It is the default mode.
+	EagerEvaluation EvaluationMode = "eager"
+	// LazyEvaluation computes only the operations print operations
+	// transitively depend on and reports the others as skipped.
+	LazyEvaluation EvaluationMode = "lazy"
+)
+
+func (mode *EvaluationMode) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	switch EvaluationMode(s) {
+	case "", EagerEvaluation, LazyEvaluation:
+		*mode = EvaluationMode(s)
+		return nil
+	default:
+		return NewError(InvalidRequestCode, "invalid evaluation mode").WithField("evaluation")
+	}
+}
+
+// Options tune a single execution. The zero value keeps the default behaviour.
+type Options struct {
+	PrintOrder PrintOrder   `json:"print_order,omitempty"`
+	Numbers    NumberMode   `json:"numbers,omitempty"`
+	Overflow   OverflowMode `json:"overflow,omitempty"`
+	// DecimalScale is the number of fractional digits kept in decimal
+	// results, DefaultDecimalScale if unset.
+	DecimalScale    *int32         `json:"decimal_scale,omitempty"`
+	DecimalRounding RoundingMode   `json:"decimal_rounding,omitempty"`
+	Evaluation      EvaluationMode `json:"evaluation,omitempty"`
+	Errors          ErrorMode      `json:"errors,omitempty"`
+	// Inputs are number literals assigned to variables before the
+	// operations run, typed according to Numbers like operand literals.
+	// Operations read them like any variable but cannot assign them.
+	Inputs map[string]Operand `json:"inputs,omitempty"`
+}
+
+// Validate checks the options that cannot be validated while decoding.
+func (o Options) Validate() error {
+	if o.DecimalScale != nil && (*o.DecimalScale < 0 || *o.DecimalScale > MaxDecimalScale) {
+		return NewError(InvalidRequestCode, "decimal scale must be between 0 and %d", MaxDecimalScale).WithField("decimal_scale")
+	}
+	if !o.DecimalRounding.valid() {
+		return NewError(InvalidRequestCode, "invalid rounding mode").WithField("decimal_rounding")
+	}
+	for name, input := range o.Inputs {
+		if parsed, err := ParseOperand(name); err != nil || parsed.StringValue == nil {
+			return NewError(InvalidRequestCode, "invalid input variable name '%s'", name).WithField("inputs")
+		}
+		if input.StringValue != nil {
+			return NewError(InvalidRequestCode, "input '%s' must be a number", name).WithField("inputs." + name)
+		}
+	}
+	return nil
+}
+
+func (o Options) decimalScale() int32 {
+	if o.DecimalScale != nil {
+		return *o.DecimalScale
+	}
+	return DefaultDecimalScale
+}
+
+// Request is the body of an execution request. It is either a bare list of
+// operations (the original format) or an object carrying the operations
+// together with execution options.
+type Request struct {
+	Operations []Operation `json:"operations"`
+	Options
+
+	legacy bool
+}
+
+func (r *Request) UnmarshalJSON(b []byte) error {
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '[' {
+		*r = Request{legacy: true}
+		return json.Unmarshal(trimmed, &r.Operations)
+	}
+
+	type plain Request
+	var req plain
+	if err := json.Unmarshal(b, &req); err != nil {
+		return err
+	}
+	*r = Request(req)
+	return nil
+}
+
+// Legacy reports whether the request used the bare list format, in which
+// case the response is a bare list of prints as well.
+func (r *Request) Legacy() bool {
+	return r.legacy
+}
+
+// Summary describes which assignments of the program were computed.
+// Variables holding intermediate results of expressions are not counted.
+type Summary struct {
+	Computed int      `json:"computed"`
+	Skipped  []string `json:"skipped"`
+}
+
+// Result is the outcome of an execution.
+type Result struct {
+	Items   []PrintOutput
+	Summary Summary
+	// Errors lists the failed operations by index when errors are collected.
+	Errors []OperationError
+}
+
+// Event reports a result of a program that is still running. Exactly one of
+// the fields is set.
+type Event struct {
+	Print *PrintOutput
+	// Error is the failure of an operation, only reported when errors are
+	// collected.
+	Error *OperationError
+	// Waiting is only reported by sessions.
+	Waiting *Waiting
+}
+
+// Waiting reports that an operation of a session cannot run until a
+// variable no operation has computed yet is assigned.
+type Waiting struct {
+	Index int    `json:"index"`
+	Var   string `json:"var"`
+	For   string `json:"for"`
+}
+
+type Response struct {
+	Items   []PrintOutput    `json:"items"`
+	Summary *Summary         `json:"summary,omitempty"`
+	Errors  []OperationError `json:"errors,omitempty"`
 }
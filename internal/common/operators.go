@@ -0,0 +1,132 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// EvalContext is passed to operator implementations.
+type EvalContext struct {
+	// Var is the variable the result is assigned to.
+	Var     string
+	Options Options
+}
+
+// DecimalScale is the number of fractional digits decimal results keep.
+func (ctx EvalContext) DecimalScale() int32 {
+	return ctx.Options.decimalScale()
+}
+
+// Operator describes a calc operator. Operators are looked up by symbol
+// wherever operations are decoded, validated, documented and computed, so
+// registering one makes it available everywhere at once.
+type Operator struct {
+	Symbol CalcAvailableOperation
+	// Arity is the number of operands the operator takes, or the minimum
+	// number of operands for variadic operators.
+	Arity    int
+	Variadic bool
+	// Kinds lists the operand kinds the operator accepts. Operands are
+	// promoted to the greatest kind among them before Apply is called, so
+	// Apply always receives operands of a single kind from this list.
+	Kinds       []ValueKind
+	Description string
+	Apply       func(ctx EvalContext, args []Value) (Value, error)
+}
+
+// CheckArity reports an error if the operator cannot take n operands.
+func (o Operator) CheckArity(n int) error {
+	if o.Variadic && n < o.Arity {
+		return NewError(InvalidOperationCode, "operator %s expects at least %d operands, got %d", o.Symbol, o.Arity, n)
+	}
+	if !o.Variadic && n != o.Arity {
+		return NewError(InvalidOperationCode, "operator %s expects %d operands, got %d", o.Symbol, o.Arity, n)
+	}
+	return nil
+}
+
+func (o Operator) accepts(kind ValueKind) bool {
+	for _, k := range o.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	operatorsMu sync.RWMutex
+	operators   = make(map[CalcAvailableOperation]Operator)
+)
+
+// RegisterOperator makes the operator available to every request. It is
+// meant to be called at startup, before the servers accept requests.
+func RegisterOperator(op Operator) error {
+	switch {
+	case op.Symbol == "":
+		return errors.New("operator symbol cannot be empty")
+	case op.Arity < 1:
+		return fmt.Errorf("operator %s: at least one operand is required", op.Symbol)
+	case len(op.Kinds) == 0:
+		return fmt.Errorf("operator %s: at least one operand kind is required", op.Symbol)
+	case op.Apply == nil:
+		return fmt.Errorf("operator %s: implementation cannot be nil", op.Symbol)
+	}
+
+	operatorsMu.Lock()
+	defer operatorsMu.Unlock()
+	if _, exists := operators[op.Symbol]; exists {
+		return fmt.Errorf("operator %s is already registered", op.Symbol)
+	}
+	operators[op.Symbol] = op
+	return nil
+}
+
+func LookupOperator(symbol CalcAvailableOperation) (Operator, bool) {
+	operatorsMu.RLock()
+	defer operatorsMu.RUnlock()
+	op, ok := operators[symbol]
+	return op, ok
+}
+
+// Operators returns every registered operator sorted by symbol.
+func Operators() []Operator {
+	operatorsMu.RLock()
+	defer operatorsMu.RUnlock()
+	result := make([]Operator, 0, len(operators))
+	for _, op := range operators {
+		result = append(result, op)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Symbol < result[j].Symbol
+	})
+	return result
+}
+
+// applyOperator checks the operands against the operator signature,
+// promotes them to a common kind and applies the operator.
+func applyOperator(ctx EvalContext, symbol CalcAvailableOperation, args ...Value) (Value, error) {
+	operator, ok := LookupOperator(symbol)
+	if !ok {
+		return Value{}, NewError(InvalidOperationCode, "invalid operation")
+	}
+	if err := operator.CheckArity(len(args)); err != nil {
+		return Value{}, err
+	}
+
+	kind := IntKind
+	for _, arg := range args {
+		kind = max(kind, arg.Kind())
+	}
+	if !operator.accepts(kind) {
+		return Value{}, NewError(InvalidOperandCode, "operator %s does not accept %s operands", symbol, kind)
+	}
+
+	promoted := make([]Value, len(args))
+	for i, arg := range args {
+		promoted[i] = arg.as(kind)
+	}
+	return operator.Apply(ctx, promoted)
+}
@@ -0,0 +1,63 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterOperator(t *testing.T) {
+	err := RegisterOperator(Operator{
+		Symbol:      "avg",
+		Arity:       2,
+		Kinds:       []ValueKind{IntKind},
+		Description: "mean of two integers",
+		Apply: func(ctx EvalContext, args []Value) (Value, error) {
+			left, _ := args[0].Int64()
+			right, _ := args[1].Int64()
+			return NewIntValue((left + right) / 2), nil
+		},
+	})
+	assert.NoError(t, err)
+
+	err = RegisterOperator(Operator{Symbol: "avg", Arity: 2, Kinds: NumericKinds, Apply: func(EvalContext, []Value) (Value, error) {
+		return Value{}, nil
+	}})
+	assert.EqualError(t, err, "operator avg is already registered")
+
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	var operations []Operation
+	err = json.Unmarshal([]byte(`[
+		{"type": "calc", "var": "x", "op": "avg", "left": 3, "right": 8},
+		{"type": "print", "var": "x"}
+	]`), &operations)
+	assert.NoError(t, err)
+
+	calculator := NewUpgradedCalculator(logger, "custom_operator")
+	actualOutputs, err := calculator.Execute(context.Background(), operations)
+	assert.NoError(t, err)
+	assert.Equal(t, []PrintOutput{{Var: "x", Value: NewIntValue(5)}}, actualOutputs)
+
+	calculator = NewUpgradedCalculator(logger, "custom_operator_kinds")
+	_, err = calculator.Execute(context.Background(), []Operation{{
+		Type:  CalcOperation,
+		Var:   "y",
+		Op:    "avg",
+		Left:  &Operand{FloatValue: new(float64)},
+		Right: &Operand{IntValue: int64Ptr(1)},
+	}})
+	assert.EqualError(t, err, "operator avg does not accept float operands")
+}
+
+func TestRegisterOperator_Invalid(t *testing.T) {
+	assert.Error(t, RegisterOperator(Operator{Symbol: "", Arity: 2}))
+	assert.Error(t, RegisterOperator(Operator{Symbol: "nullary", Arity: 0, Kinds: NumericKinds}))
+	assert.Error(t, RegisterOperator(Operator{Symbol: "nop", Arity: 2, Kinds: NumericKinds}))
+}
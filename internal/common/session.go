@@ -0,0 +1,242 @@
+package common
+
+import (
+	"sort"
+)
+
+// Session runs operations one at a time against a single calculator, so
+// variables assigned by earlier operations stay available to later ones.
+// An operation runs as soon as every variable it reads is assigned, however
+// many operations later its inputs arrive. Prints and failures are reported
+// to the observer of the calculator as they happen.
+//
+// Session is not safe for concurrent use. Operations run on the goroutine
+// calling Add, either the added one or those waiting for its variable.
+type Session struct {
+	c    *UpgradedCalculator
+	opts Options
+
+	// assigned holds every variable assigned by an accepted operation,
+	// computed or not, and names holds the variable of every operation.
+	assigned map[string]bool
+	names    map[int]string
+	// failed holds, for every variable whose operation failed, the error
+	// failing the operations reading it.
+	failed map[string]error
+	// waiting holds, by variable, the operations waiting for it.
+	waiting map[string][]sessionTask
+	// deferred holds the operations of untaken conditional branches of
+	// expressions, they run only once a cond operation takes their branch.
+	deferred map[string]sessionTask
+	reported map[int]bool
+	// notified holds the variables every operation was reported waiting for.
+	notified map[int]map[string]bool
+	computed int
+	printed  int64
+}
+
+// sessionTask is an operation of a session. Operations of an expression
+// share the index of the expr operation.
+type sessionTask struct {
+	index int
+	op    Operation
+}
+
+// NewSession starts a session on the calculator with the inputs of the
+// options defined. The evaluation and errors modes of the options do not
+// apply: every operation is computed and failures never end the session.
+func NewSession(c *UpgradedCalculator, opts Options) (*Session, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.defineInputs(opts); err != nil {
+		return nil, err
+	}
+	return &Session{
+		c:        c,
+		opts:     opts,
+		assigned: make(map[string]bool),
+		names:    make(map[int]string),
+		failed:   make(map[string]error),
+		waiting:  make(map[string][]sessionTask),
+		deferred: make(map[string]sessionTask),
+		reported: make(map[int]bool),
+		notified: make(map[int]map[string]bool),
+	}, nil
+}
+
+// Add accepts the operation with the given index and runs it if its inputs
+// are available. It returns an error, located at the operation, only if the
+// operation is invalid on its own or assigns an assigned variable; runtime
+// failures are reported to the observer.
+func (s *Session) Add(index int, op Operation) error {
+	if err := op.checkVar(); err != nil {
+		return atOperation(err, index, op.Var)
+	}
+	if err := op.CheckOperands(); err != nil {
+		return atOperation(err, index, op.Var)
+	}
+	operations := []Operation{op}
+	if op.Type == ExprOperation {
+		compiled, err := compileExpression(index, op)
+		if err != nil {
+			return &Error{Code: CodeOf(err), Message: err.Error(), Index: &index, Var: op.Var, Field: "expr", Err: err}
+		}
+		operations = compiled
+	}
+	if op.assigns() || op.Type == ExprOperation {
+		if s.assigned[op.Var] || s.c.defined(op.Var) {
+			return NewError(DuplicateAssignmentCode, "variable '%s' is already assigned", op.Var).AtOperation(index, op.Var)
+		}
+	}
+
+	s.names[index] = op.Var
+	for _, operation := range operations {
+		if operation.assigns() {
+			s.assigned[operation.Var] = true
+		}
+	}
+	for _, operation := range operations {
+		task := sessionTask{index: index, op: operation}
+		if operation.deferred {
+			s.deferred[operation.Var] = task
+			continue
+		}
+		s.schedule(task, s.inputs(operation)...)
+	}
+	return nil
+}
+
+// Close fails every operation still waiting for a variable no operation
+// assigned and reports which assignments were computed.
+func (s *Session) Close() Summary {
+	names := make([]string, 0, len(s.waiting))
+	for name := range s.waiting {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		tasks := s.waiting[name]
+		delete(s.waiting, name)
+		for _, task := range tasks {
+			s.fail(task, NewError(UndefinedVariableCode, "variable '%s' is undefined", name))
+		}
+	}
+	return Summary{Computed: s.computed, Skipped: []string{}}
+}
+
+// inputs returns the variables the operation reads before it can run. The
+// branches of a cond operation are only known once its condition is.
+func (s *Session) inputs(op Operation) []string {
+	var operands []*Operand
+	switch op.Type {
+	case CalcOperation:
+		operands = op.Operands()
+	case CondOperation:
+		operands = []*Operand{op.Cond}
+	case PrintOperation:
+		return []string{op.Var}
+	}
+	var names []string
+	for _, operand := range operands {
+		if operand.StringValue != nil {
+			names = append(names, *operand.StringValue)
+		}
+	}
+	return names
+}
+
+// schedule runs the task once every listed variable is computed. A missing
+// variable assigned by a deferred operation demands that operation, waiting
+// for any other one is reported unless it is an intermediate result.
+func (s *Session) schedule(task sessionTask, names ...string) {
+	for _, name := range names {
+		if err, failed := s.failed[name]; failed {
+			s.fail(task, err)
+			return
+		}
+		if producer, ok := s.deferred[name]; ok {
+			delete(s.deferred, name)
+			s.schedule(producer, s.inputs(producer.op)...)
+		}
+		if !s.c.defined(name) {
+			s.waiting[name] = append(s.waiting[name], task)
+			s.notify(task.index, name)
+			return
+		}
+	}
+	s.run(task)
+}
+
+func (s *Session) notify(index int, name string) {
+	if temporaryVariable(name) || s.notified[index][name] {
+		return
+	}
+	if s.notified[index] == nil {
+		s.notified[index] = make(map[string]bool)
+	}
+	s.notified[index][name] = true
+	s.c.emit(Event{Waiting: &Waiting{Index: index, Var: s.names[index], For: name}})
+}
+
+func (s *Session) run(task sessionTask) {
+	op := task.op
+	switch op.Type {
+	case CalcOperation:
+		if err := s.c.compute(op, s.opts); err != nil {
+			s.fail(task, err)
+			return
+		}
+	case CondOperation:
+		needs, err := s.c.choose(op, s.opts)
+		if err != nil {
+			s.fail(task, err)
+			return
+		}
+		if needs != "" {
+			s.schedule(task, needs)
+			return
+		}
+	case PrintOperation:
+		value, err := s.c.subscribeVariable(op.Var)
+		if err != nil {
+			s.fail(task, err)
+			return
+		}
+		s.printed++
+		seq := s.printed
+		s.c.emit(Event{Print: &PrintOutput{Var: op.Var, Value: value, Seq: &seq}})
+		return
+	}
+
+	if !temporaryVariable(op.Var) {
+		s.computed++
+	}
+	waiters := s.waiting[op.Var]
+	delete(s.waiting, op.Var)
+	for _, waiter := range waiters {
+		s.schedule(waiter, s.inputs(waiter.op)...)
+	}
+}
+
+// fail reports the failure of the task once per requested operation and
+// fails every operation waiting for its variable.
+func (s *Session) fail(task sessionTask, err error) {
+	if !s.reported[task.index] {
+		s.reported[task.index] = true
+		opErr := newOperationError(task.index, s.names[task.index], atOperation(err, task.index, s.names[task.index]), func(source int) string {
+			return s.names[source]
+		})
+		s.c.emit(Event{Error: &opErr})
+	}
+	if !task.op.assigns() {
+		return
+	}
+
+	s.failed[task.op.Var] = &upstreamError{source: sourceOf(task.index, err)}
+	waiters := s.waiting[task.op.Var]
+	delete(s.waiting, task.op.Var)
+	for _, waiter := range waiters {
+		s.fail(waiter, s.failed[task.op.Var])
+	}
+}
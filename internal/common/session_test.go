@@ -0,0 +1,62 @@
+package common
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession(t *testing.T) {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+
+	var operations []Operation
+	err := json.Unmarshal([]byte(`[
+		{"type": "print", "var": "y"},
+		{"type": "expr", "var": "y", "expr": "x != 0 ? 10 / x : 0"},
+		{"type": "calc", "var": "x", "op": "+", "left": 1, "right": 1},
+		{"type": "calc", "var": "x", "op": "+", "left": 1, "right": 2},
+		{"type": "calc", "var": "a", "op": "/", "left": "x", "right": 0},
+		{"type": "print", "var": "a"},
+		{"type": "print", "var": "missing"},
+		{"type": "print", "var": "x"}
+	]`), &operations)
+	require.NoError(t, err)
+
+	calculator := NewUpgradedCalculator(logger, "session")
+	var events []Event
+	calculator.Observe(func(event Event) {
+		events = append(events, event)
+	})
+	session, err := NewSession(calculator, Options{})
+	require.NoError(t, err)
+
+	var rejected []error
+	for i, op := range operations {
+		if err := session.Add(i, op); err != nil {
+			rejected = append(rejected, err)
+		}
+	}
+	summary := session.Close()
+
+	require.Len(t, rejected, 1)
+	assert.Equal(t, DuplicateAssignmentCode, CodeOf(rejected[0]))
+	assert.Equal(t, Summary{Computed: 2, Skipped: []string{}}, summary)
+
+	one, two := int64(1), int64(2)
+	assert.Equal(t, []Event{
+		{Waiting: &Waiting{Index: 0, Var: "y", For: "y"}},
+		{Waiting: &Waiting{Index: 1, Var: "y", For: "x"}},
+		{Print: &PrintOutput{Var: "y", Value: NewIntValue(5), Seq: &one}},
+		{Error: &OperationError{Index: 4, Var: "a", Code: DivisionByZeroCode, Message: "division by zero"}},
+		{Error: &OperationError{Index: 5, Var: "a", Code: UpstreamFailedCode, Message: "depends on failed variable 'a'"}},
+		{Waiting: &Waiting{Index: 6, Var: "missing", For: "missing"}},
+		{Print: &PrintOutput{Var: "x", Value: NewIntValue(2), Seq: &two}},
+		{Error: &OperationError{Index: 6, Var: "missing", Code: UndefinedVariableCode, Message: "variable 'missing' is undefined"}},
+	}, events)
+}
@@ -0,0 +1,201 @@
+package common
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+)
+
+type NumberMode string
+
+const (
+	// Int64Numbers keeps every value in an int64. It is the default mode.
+	Int64Numbers NumberMode = "int64"
+	// BigNumbers keeps every integer in an arbitrary-precision integer.
+	BigNumbers NumberMode = "big"
+	// DecimalNumbers keeps every value in a fixed-point decimal.
+	DecimalNumbers NumberMode = "decimal"
+)
+
+func (mode *NumberMode) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	switch NumberMode(s) {
+	case "", Int64Numbers, BigNumbers, DecimalNumbers:
+		*mode = NumberMode(s)
+		return nil
+	default:
+		return NewError(InvalidRequestCode, "invalid number mode").WithField("numbers")
+	}
+}
+
+// ValueKind is the type of a value. Kinds are ordered by promotion: an
+// operation on two values produces the greater kind of the two.
+type ValueKind int
+
+const (
+	IntKind ValueKind = iota
+	BigKind
+	DecimalKind
+	FloatKind
+)
+
+// IntegerKinds and NumericKinds are the usual operand kinds of operators.
+var (
+	IntegerKinds = []ValueKind{IntKind, BigKind}
+	NumericKinds = []ValueKind{IntKind, BigKind, DecimalKind, FloatKind}
+)
+
+func (k ValueKind) String() string {
+	switch k {
+	case IntKind:
+		return "int64"
+	case BigKind:
+		return "big"
+	case DecimalKind:
+		return "decimal"
+	case FloatKind:
+		return "float"
+	}
+	return "unknown"
+}
+
+// Value is a variable value: an int64, an arbitrary-precision integer, a
+// fixed-point decimal or a float64.
+type Value struct {
+	kind ValueKind
+	i    int64
+	b    *big.Int
+	d    Decimal
+	f    float64
+}
+
+func NewIntValue(v int64) Value {
+	return Value{kind: IntKind, i: v}
+}
+
+// NewBigValue wraps v without copying it, v must not be modified afterwards.
+func NewBigValue(v *big.Int) Value {
+	return Value{kind: BigKind, b: v}
+}
+
+func NewDecimalValue(v Decimal) Value {
+	return Value{kind: DecimalKind, d: v}
+}
+
+func NewFloatValue(v float64) Value {
+	return Value{kind: FloatKind, f: v}
+}
+
+func (v Value) Kind() ValueKind {
+	return v.kind
+}
+
+// Int64 returns an integer value as an int64, reporting false if it does not
+// fit or the value is not an integer.
+func (v Value) Int64() (int64, bool) {
+	switch v.kind {
+	case IntKind:
+		return v.i, true
+	case BigKind:
+		return v.b.Int64(), v.b.IsInt64()
+	}
+	return 0, false
+}
+
+// Big returns an integer value as an arbitrary-precision integer and nil for
+// fractional values. The result must not be modified.
+func (v Value) Big() *big.Int {
+	switch v.kind {
+	case IntKind:
+		return big.NewInt(v.i)
+	case BigKind:
+		return v.b
+	}
+	return nil
+}
+
+// Decimal returns the value as a decimal. Integers are converted exactly,
+// floats through their shortest decimal representation.
+func (v Value) Decimal() Decimal {
+	switch v.kind {
+	case IntKind:
+		return NewDecimalFromBig(big.NewInt(v.i))
+	case BigKind:
+		return NewDecimalFromBig(v.b)
+	case FloatKind:
+		d, _ := ParseDecimal(strconv.FormatFloat(v.f, 'f', -1, 64))
+		return d
+	}
+	return v.d
+}
+
+func (v Value) Float64() float64 {
+	switch v.kind {
+	case IntKind:
+		return float64(v.i)
+	case BigKind:
+		f, _ := new(big.Float).SetInt(v.b).Float64()
+		return f
+	case DecimalKind:
+		return v.d.Float64()
+	}
+	return v.f
+}
+
+// IsZero reports whether the value equals zero. Conditions treat every
+// non-zero value as true.
+func (v Value) IsZero() bool {
+	switch v.kind {
+	case BigKind:
+		return v.b.Sign() == 0
+	case DecimalKind:
+		return v.d.Sign() == 0
+	case FloatKind:
+		return v.f == 0
+	}
+	return v.i == 0
+}
+
+// as converts the value to a kind that is not lower in the promotion order.
+func (v Value) as(kind ValueKind) Value {
+	if v.kind == kind {
+		return v
+	}
+	switch kind {
+	case BigKind:
+		return NewBigValue(v.Big())
+	case DecimalKind:
+		return NewDecimalValue(v.Decimal())
+	case FloatKind:
+		return NewFloatValue(v.Float64())
+	}
+	return v
+}
+
+func (v Value) String() string {
+	switch v.kind {
+	case BigKind:
+		return v.b.String()
+	case DecimalKind:
+		return v.d.String()
+	case FloatKind:
+		return strconv.FormatFloat(v.f, 'g', -1, 64)
+	}
+	return strconv.FormatInt(v.i, 10)
+}
+
+// MarshalJSON writes int64 and float64 values as JSON numbers, big and
+// decimal values as strings, so that clients parsing numbers as doubles do
+// not lose precision.
+func (v Value) MarshalJSON() ([]byte, error) {
+	switch v.kind {
+	case BigKind, DecimalKind:
+		return json.Marshal(v.String())
+	case FloatKind:
+		return json.Marshal(v.f)
+	}
+	return []byte(strconv.FormatInt(v.i, 10)), nil
+}
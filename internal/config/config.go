@@ -17,6 +17,15 @@ type AppConfig struct {
 	GRPCTimeout            time.Duration
 	GRPCShutdownTimeout    time.Duration
 	LogLevel               string
+	// SessionTTL is the number of seconds a session lives after its last use.
+	SessionTTL          time.Duration
+	MaxSessions         int
+	MaxSessionVariables int
+	MaxSessionHistory   int
+	// SessionStorage selects where sessions are kept: "memory" or "file",
+	// the latter in the database at SessionStoragePath.
+	SessionStorage     string
+	SessionStoragePath string
 }
 
 type Config struct {
@@ -34,6 +43,12 @@ func New() *Config {
 			GRPCTimeout:            time.Duration(getEnvAsInt("GRPC_APP_TIMEOUT", 10)),
 			GRPCShutdownTimeout:    time.Duration(getEnvAsInt("GRPC_SHUTDOWN_TIMEOUT", 10)),
 			LogLevel:               getEnv("LOG_LEVEL", "PROD"),
+			SessionTTL:             time.Duration(getEnvAsInt("SESSION_TTL", 3600)),
+			MaxSessions:            getEnvAsInt("MAX_SESSIONS", 1000),
+			MaxSessionVariables:    getEnvAsInt("MAX_SESSION_VARIABLES", 10000),
+			MaxSessionHistory:      getEnvAsInt("MAX_SESSION_HISTORY", 100),
+			SessionStorage:         getEnv("SESSION_STORAGE", "memory"),
+			SessionStoragePath:     getEnv("SESSION_STORAGE_PATH", "/app/data/sessions.db"),
 		},
 	}
 }
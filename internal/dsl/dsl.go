@@ -0,0 +1,145 @@
+// Package dsl parses the line-based program format:
+//
+//	# comments run to the end of the line
+//	x = 3 + 8
+//	y = (x - 1) * 2
+//	print y
+//
+// Every line holds at most one statement. An assignment becomes an expr
+// operation and "print" a print operation, so a parsed program runs exactly
+// like the equivalent JSON operation list.
+package dsl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"upgraded-calculator/internal/common"
+)
+
+const printKeyword = "print"
+
+// Error is a diagnostic pointing at a position of the program. Line and
+// Column are 1-based, Column counts bytes.
+type Error struct {
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Msg    string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// ErrorList holds every diagnostic of a program in line order.
+type ErrorList []*Error
+
+func (l ErrorList) ErrorCode() common.ErrorCode {
+	return common.SyntaxErrorCode
+}
+
+func (l ErrorList) Error() string {
+	messages := make([]string, len(l))
+	for i, err := range l {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Parse parses a program into operations. It reports every invalid line at
+// once as an ErrorList rather than stopping at the first one.
+func Parse(src string) ([]common.Operation, error) {
+	var (
+		operations []common.Operation
+		errs       ErrorList
+	)
+	for i, line := range strings.Split(src, "\n") {
+		op, err := parseLine(i+1, line)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if op != nil {
+			operations = append(operations, *op)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return operations, nil
+}
+
+// parseLine parses a single line, it returns nil for blank and comment lines.
+func parseLine(number int, line string) (*common.Operation, *Error) {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+	line = strings.TrimRight(line, " \t\r")
+
+	start := len(line) - len(strings.TrimLeft(line, " \t"))
+	if start == len(line) {
+		return nil, nil
+	}
+	errorAt := func(offset int, format string, args ...any) *Error {
+		return &Error{Line: number, Column: offset + 1, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	name, end := scanName(line, start)
+	if name == "" {
+		return nil, errorAt(start, "expected a variable name or '%s'", printKeyword)
+	}
+
+	rest := skipSpaces(line, end)
+	if name == printKeyword && (rest == len(line) || line[rest] != '=') {
+		variable, end := scanName(line, rest)
+		switch {
+		case variable == "":
+			return nil, errorAt(rest, "expected a variable name after '%s'", printKeyword)
+		case skipSpaces(line, end) != len(line):
+			return nil, errorAt(skipSpaces(line, end), "unexpected '%s' after the printed variable", line[skipSpaces(line, end):])
+		}
+		return &common.Operation{Type: common.PrintOperation, Var: variable}, nil
+	}
+
+	if name == printKeyword {
+		return nil, errorAt(start, "'%s' cannot be used as a variable name", printKeyword)
+	}
+	if rest == len(line) || line[rest] != '=' {
+		return nil, errorAt(rest, "expected '=' after variable '%s'", name)
+	}
+
+	exprStart := skipSpaces(line, rest+1)
+	if exprStart == len(line) {
+		return nil, errorAt(exprStart, "expected an expression after '='")
+	}
+	op := common.Operation{Type: common.ExprOperation, Var: name, Expr: line[exprStart:]}
+	if err := op.CheckOperands(); err != nil {
+		var exprErr *common.ExpressionError
+		if errors.As(err, &exprErr) {
+			return nil, errorAt(exprStart+exprErr.Offset, "%s", exprErr.Msg)
+		}
+		return nil, errorAt(exprStart, "%s", err.Error())
+	}
+	return &op, nil
+}
+
+// scanName returns the variable name starting at i and the offset past it.
+// Names consist of letters only, as in JSON programs.
+func scanName(line string, i int) (string, int) {
+	end := i
+	for end < len(line) && isLetter(line[end]) {
+		end++
+	}
+	return line[i:end], end
+}
+
+func skipSpaces(line string, i int) int {
+	for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,52 @@
+package dsl
+
+import (
+	"testing"
+	"upgraded-calculator/internal/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	operations, err := Parse(`# totals
+x = 3 + 8
+	y = (x - 1) * 2   # doubled
+
+print y
+printed = max(x, y)
+print printed
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Operation{
+		{Type: common.ExprOperation, Var: "x", Expr: "3 + 8"},
+		{Type: common.ExprOperation, Var: "y", Expr: "(x - 1) * 2"},
+		{Type: common.PrintOperation, Var: "y"},
+		{Type: common.ExprOperation, Var: "printed", Expr: "max(x, y)"},
+		{Type: common.PrintOperation, Var: "printed"},
+	}, operations)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse(`x = 3 +
+y 8
+print
+print x y
+print = 1
+z = abs(1, 2)
+1 = 2
+w =
+v = (1 + 2`)
+	assert.EqualError(t, err, `line 1, column 8: unexpected end of expression
+line 2, column 3: expected '=' after variable 'y'
+line 3, column 6: expected a variable name after 'print'
+line 4, column 9: unexpected 'y' after the printed variable
+line 5, column 1: 'print' cannot be used as a variable name
+line 6, column 5: operator abs expects 1 operands, got 2
+line 7, column 1: expected a variable name or 'print'
+line 8, column 4: expected an expression after '='
+line 9, column 11: expected ')', found end of expression`)
+
+	var errs ErrorList
+	assert.ErrorAs(t, err, &errs)
+	assert.Len(t, errs, 9)
+}
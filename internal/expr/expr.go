@@ -0,0 +1,288 @@
+// Package expr parses infix arithmetic expressions such as "(x + 3) * y / 2"
+// into a syntax tree. It knows nothing about evaluation: the calculator
+// compiles the tree into its own operations.
+package expr
+
+import (
+	"fmt"
+)
+
+// Node is a node of the syntax tree. Offset is the byte offset of the node
+// in the parsed source.
+type Node interface {
+	Offset() int
+}
+
+// Number is a numeric literal kept as written in the source.
+type Number struct {
+	Text string
+	Pos  int
+}
+
+// Ident is a variable reference.
+type Ident struct {
+	Name string
+	Pos  int
+}
+
+// Unary is a prefix operator application, Op is "-" or "+".
+type Unary struct {
+	Op  string
+	X   Node
+	Pos int
+}
+
+// Binary is an infix operator application.
+type Binary struct {
+	Op   string
+	X, Y Node
+	Pos  int
+}
+
+// Call is a function-style operator application such as "max(a, b, 3)".
+type Call struct {
+	Func string
+	Args []Node
+	Pos  int
+}
+
+// Conditional is "cond ? then : else", Pos is the offset of "?".
+type Conditional struct {
+	Cond, Then, Else Node
+	Pos              int
+}
+
+func (n *Number) Offset() int      { return n.Pos }
+func (n *Ident) Offset() int       { return n.Pos }
+func (n *Unary) Offset() int       { return n.Pos }
+func (n *Binary) Offset() int      { return n.Pos }
+func (n *Call) Offset() int        { return n.Pos }
+func (n *Conditional) Offset() int { return n.Pos }
+
+// SyntaxError describes why and where parsing failed.
+type SyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at column %d: %s", e.Offset+1, e.Msg)
+}
+
+// binaryPrecedence lists infix operators from the loosest to the tightest
+// binding level. Comparisons bind looser than bitwise operators, so
+// "a & 1 == 0" is "(a & 1) == 0". "**" is handled separately as it is
+// right-associative and binds tighter than prefix operators, and the
+// conditional "?:" binds loosest of all.
+var binaryPrecedence = map[string]int{
+	"==": 1, "!=": 1, "<": 1, "<=": 1, ">": 1, ">=": 1,
+	"|":  2,
+	"^":  3,
+	"&":  4,
+	"<<": 5, ">>": 5,
+	"+": 6, "-": 6,
+	"*": 7, "/": 7, "%": 7,
+}
+
+// MaxDepth bounds the nesting of the parsed expression: parentheses, calls,
+// conditionals, prefix operators and right operands of "**" add to the
+// depth. A chain of left-associative infix operators such as "1 + 2 + 3"
+// does not, although its tree leans to the left as deep as the chain is long:
+// walk the left operands of Binary nodes iteratively. The bound keeps the
+// parser and the compilers walking the tree recursively from exhausting the
+// stack.
+const MaxDepth = 1000
+
+// Parse parses a complete expression.
+func Parse(src string) (Node, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	node, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != eofToken {
+		return nil, &SyntaxError{Offset: tok.pos, Msg: fmt.Sprintf("unexpected %s", tok)}
+	}
+	return node, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+// enter descends a level of the syntax tree at the token at pos, leave
+// returns from it.
+func (p *parser) enter(pos int) error {
+	p.depth++
+	if p.depth > MaxDepth {
+		return &SyntaxError{Offset: pos, Msg: fmt.Sprintf("expression is nested deeper than %d levels", MaxDepth)}
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != eofToken {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) expect(text string) error {
+	if tok := p.next(); tok.kind != operatorToken || tok.text != text {
+		return &SyntaxError{Offset: tok.pos, Msg: fmt.Sprintf("expected '%s', found %s", text, tok)}
+	}
+	return nil
+}
+
+// parseConditional parses "cond ? then : else", which is right-associative:
+// "a ? b : c ? d : e" is "a ? b : (c ? d : e)".
+func (p *parser) parseConditional() (Node, error) {
+	if err := p.enter(p.peek().pos); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	cond, err := p.parseBinary(1)
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	if tok.kind != operatorToken || tok.text != "?" {
+		return cond, nil
+	}
+	p.next()
+	then, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+	return &Conditional{Cond: cond, Then: then, Else: otherwise, Pos: tok.pos}, nil
+}
+
+// parseBinary parses a chain of left-associative infix operators binding at
+// least as tight as minPrecedence.
+func (p *parser) parseBinary(minPrecedence int) (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		precedence, ok := binaryPrecedence[tok.text]
+		if tok.kind != operatorToken || !ok || precedence < minPrecedence {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseBinary(precedence + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: tok.text, X: left, Y: right, Pos: tok.pos}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if err := p.enter(p.peek().pos); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	if tok := p.peek(); tok.kind == operatorToken && (tok.text == "-" || tok.text == "+") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{Op: tok.text, X: x, Pos: tok.pos}, nil
+	}
+	return p.parsePower()
+}
+
+// parsePower parses "**", which is right-associative and binds tighter than
+// a prefix minus on its left: "-2 ** 2" is "-(2 ** 2)".
+func (p *parser) parsePower() (Node, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind == operatorToken && tok.text == "**" {
+		p.next()
+		exponent, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Binary{Op: tok.text, X: base, Y: exponent, Pos: tok.pos}, nil
+	}
+	return base, nil
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == numberToken:
+		return &Number{Text: tok.text, Pos: tok.pos}, nil
+	case tok.kind == identToken:
+		if next := p.peek(); next.kind == operatorToken && next.text == "(" {
+			p.next()
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			return &Call{Func: tok.text, Args: args, Pos: tok.pos}, nil
+		}
+		return &Ident{Name: tok.text, Pos: tok.pos}, nil
+	case tok.kind == operatorToken && tok.text == "(":
+		node, err := p.parseConditional()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(")"); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+	return nil, &SyntaxError{Offset: tok.pos, Msg: fmt.Sprintf("unexpected %s", tok)}
+}
+
+// parseArgs parses a comma separated argument list after the opening parenthesis.
+func (p *parser) parseArgs() ([]Node, error) {
+	var args []Node
+	if tok := p.peek(); tok.kind == operatorToken && tok.text == ")" {
+		p.next()
+		return args, nil
+	}
+	for {
+		arg, err := p.parseConditional()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		tok := p.next()
+		if tok.kind == operatorToken && tok.text == ")" {
+			return args, nil
+		}
+		if tok.kind != operatorToken || tok.text != "," {
+			return nil, &SyntaxError{Offset: tok.pos, Msg: fmt.Sprintf("expected ',' or ')', found %s", tok)}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// format prints the tree in prefix notation.
+func format(node Node) string {
+	switch n := node.(type) {
+	case *Number:
+		return n.Text
+	case *Ident:
+		return n.Name
+	case *Unary:
+		return fmt.Sprintf("(%s %s)", n.Op, format(n.X))
+	case *Binary:
+		return fmt.Sprintf("(%s %s %s)", n.Op, format(n.X), format(n.Y))
+	case *Call:
+		args := make([]string, len(n.Args))
+		for i, arg := range n.Args {
+			args[i] = format(arg)
+		}
+		return fmt.Sprintf("(%s %s)", n.Func, strings.Join(args, " "))
+	case *Conditional:
+		return fmt.Sprintf("(? %s %s %s)", format(n.Cond), format(n.Then), format(n.Else))
+	}
+	return "?"
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"(x + 3) * y / 2", "(/ (* (+ x 3) y) 2)"},
+		{"1 + 2 * 3", "(+ 1 (* 2 3))"},
+		{"1 - 2 - 3", "(- (- 1 2) 3)"},
+		{"2 ** 3 ** 2", "(** 2 (** 3 2))"},
+		{"-2 ** 2", "(- (** 2 2))"},
+		{"2 ** -1", "(** 2 (- 1))"},
+		{"a | b ^ c & d << 1 + 2", "(| a (^ b (& c (<< d (+ 1 2)))))"},
+		{"max(a, b * 2, 1.5e3)", "(max a (* b 2) 1.5e3)"},
+		{"abs(-x) % 3", "(% (abs (- x)) 3)"},
+		{"  .5  ", ".5"},
+		{"a & 1 == 0", "(== (& a 1) 0)"},
+		{"a + 1 <= b << 2", "(<= (+ a 1) (<< b 2))"},
+		{"x != 0 ? 10 / x : -1", "(? (!= x 0) (/ 10 x) (- 1))"},
+		{"a ? b : c ? d : e", "(? a b (? c d e))"},
+		{"max(a > b ? a : b, 0)", "(max (? (> a b) a b) 0)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			node, err := Parse(tt.src)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, format(node))
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"", "syntax error at column 1: unexpected end of expression"},
+		{"1 +", "syntax error at column 4: unexpected end of expression"},
+		{"(1 + 2", "syntax error at column 7: expected ')', found end of expression"},
+		{"1 2", "syntax error at column 3: unexpected '2'"},
+		{"x = 1", "syntax error at column 3: unexpected character '='"},
+		{"max(1; 2)", "syntax error at column 6: unexpected character ';'"},
+		{"max(1 2)", "syntax error at column 7: expected ',' or ')', found '2'"},
+		{"a ? b", "syntax error at column 6: expected ':', found end of expression"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := Parse(tt.src)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestParseDepth(t *testing.T) {
+	_, err := Parse(strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100))
+	assert.NoError(t, err)
+
+	node, err := Parse(strings.Repeat("1 + ", 100000) + "1")
+	assert.NoError(t, err)
+	assert.IsType(t, &Binary{}, node)
+
+	for name, src := range map[string]string{
+		"parentheses": strings.Repeat("(", 100000) + "1" + strings.Repeat(")", 100000),
+		"prefix":      strings.Repeat("-", 100000) + "1",
+		"power":       strings.Repeat("2 ** ", 100000) + "2",
+		"conditional": strings.Repeat("1 ? 2 : ", 100000) + "3",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(src)
+			var syntaxErr *SyntaxError
+			if assert.ErrorAs(t, err, &syntaxErr) {
+				assert.Contains(t, syntaxErr.Msg, "nested deeper than")
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	eofToken tokenKind = iota
+	numberToken
+	identToken
+	operatorToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == eofToken {
+		return "end of expression"
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+// operators lists operator tokens, longer ones first so that "**" is not
+// read as two "*" and "<=" is not read as "<".
+var operators = []string{
+	"**", "<<", ">>", "==", "!=", "<=", ">=",
+	"+", "-", "*", "/", "%", "&", "|", "^", "<", ">", "?", ":", "(", ")", ",",
+}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			end := scanNumber(src, i)
+			tokens = append(tokens, token{kind: numberToken, text: src[i:end], pos: i})
+			i = end
+		case isLetter(c):
+			end := i
+			for end < len(src) && isLetter(src[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: identToken, text: src[i:end], pos: i})
+			i = end
+		default:
+			op := matchOperator(src[i:])
+			if op == "" {
+				return nil, &SyntaxError{Offset: i, Msg: fmt.Sprintf("unexpected character '%c'", c)}
+			}
+			tokens = append(tokens, token{kind: operatorToken, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: eofToken, pos: len(src)}), nil
+}
+
+// scanNumber returns the end of the number literal starting at i: digits
+// with an optional fraction and an optional exponent.
+func scanNumber(src string, i int) int {
+	for i < len(src) && isDigit(src[i]) {
+		i++
+	}
+	if i < len(src) && src[i] == '.' {
+		i++
+		for i < len(src) && isDigit(src[i]) {
+			i++
+		}
+	}
+	if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+		j := i + 1
+		if j < len(src) && (src[j] == '+' || src[j] == '-') {
+			j++
+		}
+		if j < len(src) && isDigit(src[j]) {
+			for i = j; i < len(src) && isDigit(src[i]); i++ {
+			}
+		}
+	}
+	return i
+}
+
+func matchOperator(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -3,13 +3,33 @@ package grpc
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"math"
+	"math/big"
 	"upgraded-calculator/gen"
 	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/dsl"
+	"upgraded-calculator/internal/programs"
+	"upgraded-calculator/internal/sessions"
 )
 
 type CalculatorGRPC struct {
-	logger *slog.Logger
+	logger   *slog.Logger
+	sessions *sessions.Manager
+	programs *programs.Library
+}
+
+// preparedRequest is a validated request ready to run.
+type preparedRequest struct {
+	operations []common.Operation
+	opts       common.Options
+	// kept maps the index of an operation passed to the calculator to its
+	// index in the request, they differ once operations are dropped. It is
+	// nil for programs.
+	kept    []int
+	dropped invalidOperationsError
 }
 
 func (ca *CalculatorGRPC) Execute(
@@ -18,26 +38,214 @@ func (ca *CalculatorGRPC) Execute(
 ) (response *gen.Response, err error) {
 	ca.logger.Info("Processing GRPC request with request_id", "request_id", ctx.Value("request_id"))
 	c := common.NewUpgradedCalculator(ca.logger, ctx.Value("request_id").(string))
-	var operations []common.Operation
-	for _, op := range request.GetOperation() {
-		if validatedOp, err := ca.validateAndParseOperation(op); err == nil {
-			operations = append(operations, *validatedOp)
-		} else {
-			ca.logger.Error(err.Error())
-		}
+	prepared, err := ca.prepare(request)
+	if err != nil {
+		return nil, err
 	}
-	result, err := c.Execute(ctx, operations)
+	result, err := c.Run(ctx, prepared.operations, prepared.opts)
 	if err != nil {
 		ca.logger.Error(err.Error())
-		return nil, err
+		return nil, relocate(err, prepared.kept)
 	}
-	formedResponse, _ := ca.formResponse(result)
-	resp := &gen.Response{Items: formedResponse}
+	resp := ca.formResult(prepared, result)
 	ca.logger.Info("Response formed", "request_id", ctx.Value("request_id").(string))
 	c = nil
 	return resp, nil
 }
 
+// ExecuteStream runs the request like Execute and passes every result to
+// send as soon as the calculator reports it, ending with the summary. A
+// failed send cancels the execution.
+func (ca *CalculatorGRPC) ExecuteStream(
+	ctx context.Context,
+	request *gen.Request,
+	send func(*gen.Event) error,
+) error {
+	ca.logger.Info("Processing GRPC stream with request_id", "request_id", ctx.Value("request_id"))
+	c := common.NewUpgradedCalculator(ca.logger, ctx.Value("request_id").(string))
+	prepared, err := ca.prepare(request)
+	if err != nil {
+		return err
+	}
+	for _, dropped := range prepared.dropped {
+		if err := send(&gen.Event{Event: &gen.Event_Dropped{Dropped: invalidOperation(dropped)}}); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var sendErr error
+	c.Observe(func(event common.Event) {
+		if sendErr != nil {
+			return
+		}
+		switch {
+		case event.Print != nil:
+			sendErr = send(&gen.Event{Event: &gen.Event_Variable{Variable: formVariable(*event.Print)}})
+		case event.Error != nil:
+			sendErr = send(&gen.Event{Event: &gen.Event_Error{Error: prepared.operationError(*event.Error)}})
+		}
+		if sendErr != nil {
+			ca.logger.Error(sendErr.Error())
+			cancel()
+		}
+	})
+	result, err := c.Run(ctx, prepared.operations, prepared.opts)
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		ca.logger.Error(err.Error())
+		return relocate(err, prepared.kept)
+	}
+	ca.logger.Info("Stream completed", "request_id", ctx.Value("request_id").(string))
+	return send(&gen.Event{Event: &gen.Event_Summary{Summary: summary(result.Summary)}})
+}
+
+// Session runs the operations returned by recv on one calculator as they
+// arrive and passes their results to send, see common.Session. It ends with
+// the summary once recv returns io.EOF.
+func (ca *CalculatorGRPC) Session(
+	ctx context.Context,
+	recv func() (*gen.Operation, error),
+	send func(*gen.Event) error,
+) error {
+	ca.logger.Info("Processing GRPC session with request_id", "request_id", ctx.Value("request_id"))
+	c := common.NewUpgradedCalculator(ca.logger, ctx.Value("request_id").(string))
+	var sendErr error
+	c.Observe(func(event common.Event) {
+		if sendErr != nil {
+			return
+		}
+		switch {
+		case event.Print != nil:
+			sendErr = send(&gen.Event{Event: &gen.Event_Variable{Variable: formVariable(*event.Print)}})
+		case event.Error != nil:
+			sendErr = send(&gen.Event{Event: &gen.Event_Error{Error: operationError(*event.Error)}})
+		}
+	})
+	session, err := common.NewSession(c, common.Options{})
+	if err != nil {
+		return err
+	}
+
+	for index := 0; ; index++ {
+		op, err := recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		validatedOp, err := ca.validateAndParseOperation(op)
+		if err == nil {
+			err = session.Add(index, *validatedOp)
+		}
+		if err != nil {
+			ca.logger.Error(err.Error(), "index", index)
+			sendErr = send(&gen.Event{Event: &gen.Event_Error{Error: invalidOperation(locate(err, index, op.Var))}})
+		}
+		if sendErr != nil {
+			return sendErr
+		}
+	}
+
+	summary := summary(session.Close())
+	if sendErr != nil {
+		return sendErr
+	}
+	ca.logger.Info("Session closed", "request_id", ctx.Value("request_id").(string))
+	return send(&gen.Event{Event: &gen.Event_Summary{Summary: summary}})
+}
+
+// prepare parses and validates the request, see Request.validation. A
+// missing request is an empty one.
+func (ca *CalculatorGRPC) prepare(request *gen.Request) (*preparedRequest, error) {
+	if request == nil {
+		request = &gen.Request{}
+	}
+	prepared := &preparedRequest{}
+	var err error
+	if request.Program != nil {
+		if len(request.GetOperation()) > 0 {
+			err = common.NewError(common.InvalidRequestCode, "operation and program cannot be used together").WithField("program")
+			ca.logger.Error(err.Error())
+			return nil, err
+		}
+		if prepared.operations, err = dsl.Parse(request.GetProgram()); err != nil {
+			ca.logger.Error(err.Error())
+			return nil, err
+		}
+	}
+	lenient := false
+	switch request.GetValidation() {
+	case "", strictValidation:
+	case lenientValidation:
+		lenient = true
+	default:
+		err = common.NewError(common.InvalidRequestCode, "invalid validation mode from request").WithField("validation")
+		ca.logger.Error(err.Error())
+		return nil, err
+	}
+
+	for i, op := range request.GetOperation() {
+		validatedOp, err := ca.validateAndParseOperation(op)
+		if err != nil {
+			ca.logger.Error(err.Error(), "index", i)
+			prepared.dropped = append(prepared.dropped, locate(err, i, op.Var))
+			continue
+		}
+		prepared.operations = append(prepared.operations, *validatedOp)
+		prepared.kept = append(prepared.kept, i)
+	}
+	if len(prepared.dropped) > 0 && !lenient {
+		return nil, prepared.dropped
+	}
+
+	if prepared.opts, err = ca.parseOptions(request); err != nil {
+		ca.logger.Error(err.Error())
+		return nil, err
+	}
+	return prepared, nil
+}
+
+// operationError converts the failure of an executed operation, indexed
+// as in the request.
+func (p *preparedRequest) operationError(opErr common.OperationError) *gen.OperationError {
+	if p.kept != nil {
+		opErr.Index = p.kept[opErr.Index]
+	}
+	return operationError(opErr)
+}
+
+func operationError(opErr common.OperationError) *gen.OperationError {
+	return &gen.OperationError{
+		Index:   int32(opErr.Index),
+		Var:     opErr.Var,
+		Code:    string(opErr.Code),
+		Message: opErr.Message,
+	}
+}
+
+// invalidOperation converts the error of an operation rejected before
+// execution.
+func invalidOperation(err *common.Error) *gen.OperationError {
+	return &gen.OperationError{
+		Index:   int32(*err.Index),
+		Var:     err.Var,
+		Code:    string(err.Code),
+		Message: err.Message,
+	}
+}
+
+func summary(s common.Summary) *gen.Summary {
+	return &gen.Summary{
+		Computed: int64(s.Computed),
+		Skipped:  s.Skipped,
+	}
+}
+
 func (ca *CalculatorGRPC) validateAndParseOperation(op *gen.Operation) (*common.Operation, error) {
 	result := common.Operation{}
 	result.Var = op.Var
@@ -45,40 +253,204 @@ func (ca *CalculatorGRPC) validateAndParseOperation(op *gen.Operation) (*common.
 	case common.CalcOperation:
 		result.Type = common.OperationType(op.Type)
 		if op.Op == nil {
-			return nil, errors.New("operation cannot be nil")
+			return nil, common.NewError(common.InvalidOperationCode, "operation cannot be nil").WithField("op")
 		}
-		switch common.CalcAvailableOperation(*op.Op) {
-		case common.Add, common.Sub, common.Mul, common.Div:
-			result.Op = common.CalcAvailableOperation(*op.Op)
-		default:
-			return nil, errors.New("invalid operation type from request")
+		if _, ok := common.LookupOperator(common.CalcAvailableOperation(*op.Op)); !ok {
+			return nil, common.NewError(common.InvalidOperationCode, "invalid operation type from request").WithField("op")
+		}
+		result.Op = common.CalcAvailableOperation(*op.Op)
+
+		if op.Left != nil {
+			left, err := ca.parseOperand(op.Left)
+			if err != nil {
+				return nil, withField(err, "left")
+			}
+			result.Left = left
+		}
+
+		if op.Right != nil {
+			right, err := ca.parseOperand(op.Right)
+			if err != nil {
+				return nil, withField(err, "right")
+			}
+			result.Right = right
 		}
 
-		switch v := op.Left.GetValue().(type) {
-		case *gen.Operand_Number:
-			result.Left = &common.Operand{IntValue: &v.Number, StringValue: nil}
-		case *gen.Operand_Variable:
-			result.Left = &common.Operand{IntValue: nil, StringValue: &v.Variable}
+		for i, arg := range op.Args {
+			parsed, err := ca.parseOperand(arg)
+			if err != nil {
+				return nil, withField(err, fmt.Sprintf("args[%d]", i))
+			}
+			result.Args = append(result.Args, *parsed)
 		}
 
-		switch v := op.Right.GetValue().(type) {
-		case *gen.Operand_Number:
-			result.Right = &common.Operand{IntValue: &v.Number, StringValue: nil}
-		case *gen.Operand_Variable:
-			result.Right = &common.Operand{IntValue: nil, StringValue: &v.Variable}
+		if err := result.CheckOperands(); err != nil {
+			return nil, err
+		}
+	case common.ExprOperation:
+		result.Type = common.OperationType(op.Type)
+		result.Expr = op.GetExpr()
+		if err := result.CheckOperands(); err != nil {
+			return nil, err
+		}
+	case common.CondOperation:
+		result.Type = common.OperationType(op.Type)
+		var err error
+		if result.Cond, err = ca.parseOptionalOperand(op.Cond); err != nil {
+			return nil, withField(err, "cond")
+		}
+		if result.Then, err = ca.parseOptionalOperand(op.Then); err != nil {
+			return nil, withField(err, "then")
+		}
+		if result.Else, err = ca.parseOptionalOperand(op.Else); err != nil {
+			return nil, withField(err, "else")
+		}
+		if err := result.CheckOperands(); err != nil {
+			return nil, err
 		}
 	case common.PrintOperation:
 		result.Type = common.OperationType(op.Type)
 	default:
-		return nil, errors.New("invalid operation type from request")
+		return nil, common.NewError(common.InvalidOperationCode, "invalid operation type from request").WithField("type")
 	}
 	return &result, nil
 }
 
+// parseOptionalOperand parses the operand if it is set and returns nil otherwise.
+func (ca *CalculatorGRPC) parseOptionalOperand(op *gen.Operand) (*common.Operand, error) {
+	if op == nil {
+		return nil, nil
+	}
+	return ca.parseOperand(op)
+}
+
+func (ca *CalculatorGRPC) parseOperand(op *gen.Operand) (*common.Operand, error) {
+	switch v := op.GetValue().(type) {
+	case *gen.Operand_Number:
+		return &common.Operand{IntValue: &v.Number, StringValue: nil}, nil
+	case *gen.Operand_Variable:
+		if !common.IsVariableName(v.Variable) {
+			return nil, common.NewError(common.InvalidOperandCode, "invalid variable name '%s'", v.Variable)
+		}
+		return &common.Operand{IntValue: nil, StringValue: &v.Variable}, nil
+	case *gen.Operand_BigNumber:
+		num, ok := new(big.Int).SetString(v.BigNumber, 10)
+		if !ok {
+			return nil, common.NewError(common.InvalidOperandCode, "invalid big number operand from request")
+		}
+		return &common.Operand{BigValue: num}, nil
+	case *gen.Operand_FloatNumber:
+		if err := checkFinite(v.FloatNumber); err != nil {
+			return nil, err
+		}
+		return &common.Operand{FloatValue: &v.FloatNumber}, nil
+	case *gen.Operand_DecimalNumber:
+		num, err := common.ParseDecimal(v.DecimalNumber)
+		if err != nil {
+			return nil, err
+		}
+		return &common.Operand{DecimalValue: &num}, nil
+	}
+	return nil, common.NewError(common.InvalidOperandCode, "operand value cannot be empty")
+}
+
+// checkFinite rejects NaN and infinite float operands, which protobuf can
+// carry but JSON cannot.
+func checkFinite(f float64) error {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return common.NewError(common.InvalidOperandCode, "float operand %g is not finite", f)
+	}
+	return nil
+}
+
+func (ca *CalculatorGRPC) parseOptions(request *gen.Request) (common.Options, error) {
+	opts := common.Options{}
+	switch order := common.PrintOrder(request.GetPrintOrder()); order {
+	case "", common.RequestPrintOrder, common.CompletionPrintOrder:
+		opts.PrintOrder = order
+	default:
+		return opts, common.NewError(common.InvalidRequestCode, "invalid print order from request").WithField("print_order")
+	}
+	switch mode := common.NumberMode(request.GetNumbers()); mode {
+	case "", common.Int64Numbers, common.BigNumbers, common.DecimalNumbers:
+		opts.Numbers = mode
+	default:
+		return opts, common.NewError(common.InvalidRequestCode, "invalid numbers mode from request").WithField("numbers")
+	}
+	switch mode := common.OverflowMode(request.GetOverflow()); mode {
+	case "", common.WrappingOverflow, common.CheckedOverflow, common.SaturatingOverflow:
+		opts.Overflow = mode
+	default:
+		return opts, common.NewError(common.InvalidRequestCode, "invalid overflow mode from request").WithField("overflow")
+	}
+	switch mode := common.EvaluationMode(request.GetEvaluation()); mode {
+	case "", common.EagerEvaluation, common.LazyEvaluation:
+		opts.Evaluation = mode
+	default:
+		return opts, common.NewError(common.InvalidRequestCode, "invalid evaluation mode from request").WithField("evaluation")
+	}
+	switch mode := common.ErrorMode(request.GetErrors()); mode {
+	case "", common.FailFastErrors, common.CollectErrors:
+		opts.Errors = mode
+	default:
+		return opts, common.NewError(common.InvalidRequestCode, "invalid errors mode from request").WithField("errors")
+	}
+	opts.DecimalScale = request.DecimalScale
+	opts.DecimalRounding = common.RoundingMode(request.GetDecimalRounding())
+	for name, input := range request.GetInputs() {
+		parsed, err := ca.parseOperand(input)
+		if err != nil {
+			return opts, withField(err, "inputs."+name)
+		}
+		if opts.Inputs == nil {
+			opts.Inputs = make(map[string]common.Operand, len(request.GetInputs()))
+		}
+		opts.Inputs[name] = *parsed
+	}
+	if err := opts.Validate(); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// formResult converts the result of the prepared request.
+func (ca *CalculatorGRPC) formResult(prepared *preparedRequest, result common.Result) *gen.Response {
+	formedResponse, _ := ca.formResponse(result.Items)
+	resp := &gen.Response{
+		Items:   formedResponse,
+		Summary: summary(result.Summary),
+	}
+	for _, opErr := range result.Errors {
+		resp.Errors = append(resp.Errors, prepared.operationError(opErr))
+	}
+	for _, dropped := range prepared.dropped {
+		resp.Dropped = append(resp.Dropped, invalidOperation(dropped))
+	}
+	return resp
+}
+
 func (ca *CalculatorGRPC) formResponse(outputList []common.PrintOutput) ([]*gen.Variable, error) {
 	result := make([]*gen.Variable, 0, len(outputList))
 	for _, op := range outputList {
-		result = append(result, &gen.Variable{Var: op.Var, Value: op.Value})
+		result = append(result, formVariable(op))
 	}
 	return result, nil
 }
+
+func formVariable(output common.PrintOutput) *gen.Variable {
+	result := &gen.Variable{Var: output.Var, Seq: output.Seq}
+	switch output.Value.Kind() {
+	case common.BigKind:
+		bigValue := output.Value.String()
+		result.BigValue = &bigValue
+	case common.DecimalKind:
+		decimalValue := output.Value.String()
+		result.DecimalValue = &decimalValue
+	case common.FloatKind:
+		floatValue := output.Value.Float64()
+		result.FloatValue = &floatValue
+	default:
+		result.Value, _ = output.Value.Int64()
+	}
+	return result
+}
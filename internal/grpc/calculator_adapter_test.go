@@ -0,0 +1,230 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math"
+	"testing"
+	"upgraded-calculator/gen"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func number(n int64) *gen.Operand {
+	return &gen.Operand{Value: &gen.Operand_Number{Number: n}}
+}
+
+func variable(name string) *gen.Operand {
+	return &gen.Operand{Value: &gen.Operand_Variable{Variable: name}}
+}
+
+func invalidRequest() *gen.Request {
+	add, unknown := "+", "unknown"
+	return &gen.Request{Operation: []*gen.Operation{
+		{Type: "calc", Op: &unknown, Var: "a", Left: number(1), Right: number(2)},
+		{Type: "calc", Op: &add, Var: "x", Left: number(1), Right: number(2)},
+		{Type: "calc", Op: &add, Var: "b", Left: number(1), Right: &gen.Operand{}},
+		{Type: "print", Var: "x"},
+	}}
+}
+
+func execute(t *testing.T, request *gen.Request) (*gen.Response, error) {
+	t.Helper()
+	ca := &CalculatorGRPC{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	ctx := context.WithValue(context.Background(), "request_id", "test")
+	return ca.Execute(ctx, request)
+}
+
+func TestExecute_StrictValidation(t *testing.T) {
+	_, err := execute(t, invalidRequest())
+	require.Error(t, err)
+
+	st := status.Convert(statusError(err))
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	var violations []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.FieldViolations {
+				violations = append(violations, violation.Field)
+			}
+		}
+	}
+	assert.Equal(t, []string{"operation[0].op", "operation[2].right"}, violations)
+}
+
+func TestExecute_LenientValidation(t *testing.T) {
+	request := invalidRequest()
+	lenient := lenientValidation
+	request.Validation = &lenient
+	add := "+"
+	request.Operation = append(request.Operation,
+		&gen.Operation{Type: "calc", Op: &add, Var: "y", Left: variable("x"), Right: variable("missing")})
+	errorsMode := "collect"
+	request.Errors = &errorsMode
+
+	_, err := execute(t, request)
+	st := status.Convert(statusError(err))
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			assert.Equal(t, "UNDEFINED_VARIABLE", info.Reason)
+			assert.Equal(t, "4", info.Metadata["index"])
+		}
+	}
+
+	request.Operation = request.Operation[:4]
+	response, err := execute(t, request)
+	require.NoError(t, err)
+	assert.Len(t, response.Items, 1)
+	assert.Equal(t, int64(3), response.Items[0].Value)
+	require.Len(t, response.Dropped, 2)
+	assert.Equal(t, int32(0), response.Dropped[0].Index)
+	assert.Equal(t, "INVALID_OPERATION", response.Dropped[0].Code)
+	assert.Equal(t, int32(2), response.Dropped[1].Index)
+	assert.Equal(t, "INVALID_OPERAND", response.Dropped[1].Code)
+}
+
+func TestExecuteStream(t *testing.T) {
+	request := invalidRequest()
+	lenient := lenientValidation
+	request.Validation = &lenient
+	errorsMode := "collect"
+	request.Errors = &errorsMode
+	div := "/"
+	request.Operation = append(request.Operation,
+		&gen.Operation{Type: "calc", Op: &div, Var: "y", Left: variable("x"), Right: number(0)},
+		&gen.Operation{Type: "print", Var: "y"})
+
+	ca := &CalculatorGRPC{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	ctx := context.WithValue(context.Background(), "request_id", "test")
+	var events []*gen.Event
+	err := ca.ExecuteStream(ctx, request, func(event *gen.Event) error {
+		events = append(events, event)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 6)
+	assert.Equal(t, int32(0), events[0].GetDropped().GetIndex())
+	assert.Equal(t, int32(2), events[1].GetDropped().GetIndex())
+	var failed []int32
+	for _, event := range events[2:5] {
+		if event.GetVariable() != nil {
+			assert.Equal(t, "x", event.GetVariable().Var)
+		} else {
+			failed = append(failed, event.GetError().GetIndex())
+		}
+	}
+	assert.ElementsMatch(t, []int32{4, 5}, failed)
+	assert.Equal(t, int64(1), events[5].GetSummary().GetComputed())
+}
+
+func TestSession(t *testing.T) {
+	add, div := "+", "/"
+	operations := []*gen.Operation{
+		{Type: "print", Var: "y"},
+		{Type: "calc", Op: &add, Var: "x", Left: number(1), Right: number(2)},
+		{Type: "calc", Op: &add, Var: "y", Left: variable("x"), Right: number(1)},
+		{Type: "calc", Op: &div, Var: "z", Left: variable("y"), Right: &gen.Operand{}},
+		{Type: "print", Var: "w"},
+	}
+
+	ca := &CalculatorGRPC{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	ctx := context.WithValue(context.Background(), "request_id", "test")
+	var (
+		events   []*gen.Event
+		received int
+	)
+	err := ca.Session(ctx, func() (*gen.Operation, error) {
+		if received == len(operations) {
+			return nil, io.EOF
+		}
+		received++
+		return operations[received-1], nil
+	}, func(event *gen.Event) error {
+		events = append(events, event)
+		// Results are sent before the next operation is received.
+		if event.GetVariable() != nil {
+			assert.Equal(t, 3, received)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+	assert.Equal(t, int64(4), events[0].GetVariable().GetValue())
+	assert.Equal(t, "INVALID_OPERAND", events[1].GetError().GetCode())
+	assert.Equal(t, int32(3), events[1].GetError().GetIndex())
+	assert.Equal(t, "UNDEFINED_VARIABLE", events[2].GetError().GetCode())
+	assert.Equal(t, int32(4), events[2].GetError().GetIndex())
+	assert.Equal(t, int64(2), events[3].GetSummary().GetComputed())
+}
+
+func TestExecute_Inputs(t *testing.T) {
+	add := "+"
+	request := &gen.Request{
+		Operation: []*gen.Operation{
+			{Type: "calc", Op: &add, Var: "y", Left: variable("x"), Right: number(1)},
+			{Type: "print", Var: "y"},
+		},
+		Inputs: map[string]*gen.Operand{"x": number(41)},
+	}
+	response, err := execute(t, request)
+	require.NoError(t, err)
+	require.Len(t, response.Items, 1)
+	assert.Equal(t, int64(42), response.Items[0].Value)
+
+	request.Inputs = map[string]*gen.Operand{"x": variable("z")}
+	_, err = execute(t, request)
+	st := status.Convert(statusError(err))
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			require.Len(t, badRequest.FieldViolations, 1)
+			assert.Equal(t, "inputs.x", badRequest.FieldViolations[0].Field)
+		}
+	}
+}
+
+func TestExecute_NonFiniteFloat(t *testing.T) {
+	add := "+"
+	_, err := execute(t, &gen.Request{Operation: []*gen.Operation{
+		{Type: "calc", Op: &add, Var: "x", Left: number(1), Right: &gen.Operand{Value: &gen.Operand_FloatNumber{FloatNumber: math.NaN()}}},
+		{Type: "print", Var: "x"},
+	}})
+	st := status.Convert(statusError(err))
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	var violations []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.FieldViolations {
+				violations = append(violations, violation.Field)
+			}
+		}
+	}
+	assert.Equal(t, []string{"operation[0].right"}, violations)
+}
+
+func TestExecute_InvalidVariableName(t *testing.T) {
+	add := "+"
+	for _, name := range []string{"", "a1", "x$0.1"} {
+		_, err := execute(t, &gen.Request{Operation: []*gen.Operation{
+			{Type: "calc", Op: &add, Var: "x", Left: variable(name), Right: number(1)},
+			{Type: "print", Var: "x"},
+		}})
+		st := status.Convert(statusError(err))
+		assert.Equal(t, codes.InvalidArgument, st.Code(), name)
+		var violations []string
+		for _, detail := range st.Details() {
+			if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+				for _, violation := range badRequest.FieldViolations {
+					violations = append(violations, violation.Field)
+				}
+			}
+		}
+		assert.Equal(t, []string{"operation[0].left"}, violations, name)
+	}
+}
@@ -0,0 +1,340 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+	genv2 "upgraded-calculator/gen/v2"
+	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/dsl"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// CalculatorGRPCV2 serves the calculator.v2 API. The schema already rules
+// out most malformed operations, so only enum values unknown to this server,
+// missing operands and operand counts are left to validate.
+type CalculatorGRPCV2 struct {
+	logger *slog.Logger
+}
+
+var (
+	operationTypesV2 = map[genv2.OperationType]common.OperationType{
+		genv2.OperationType_OPERATION_TYPE_CALC:  common.CalcOperation,
+		genv2.OperationType_OPERATION_TYPE_PRINT: common.PrintOperation,
+		genv2.OperationType_OPERATION_TYPE_EXPR:  common.ExprOperation,
+		genv2.OperationType_OPERATION_TYPE_COND:  common.CondOperation,
+	}
+	operatorsV2 = map[genv2.Operator]common.CalcAvailableOperation{
+		genv2.Operator_OPERATOR_ADD:      common.Add,
+		genv2.Operator_OPERATOR_SUB:      common.Sub,
+		genv2.Operator_OPERATOR_MUL:      common.Mul,
+		genv2.Operator_OPERATOR_DIV:      common.Div,
+		genv2.Operator_OPERATOR_MOD:      common.Mod,
+		genv2.Operator_OPERATOR_POW:      common.Pow,
+		genv2.Operator_OPERATOR_AND:      common.And,
+		genv2.Operator_OPERATOR_OR:       common.Or,
+		genv2.Operator_OPERATOR_XOR:      common.Xor,
+		genv2.Operator_OPERATOR_SHL:      common.Shl,
+		genv2.Operator_OPERATOR_SHR:      common.Shr,
+		genv2.Operator_OPERATOR_EQ:       common.Eq,
+		genv2.Operator_OPERATOR_NE:       common.Ne,
+		genv2.Operator_OPERATOR_LT:       common.Lt,
+		genv2.Operator_OPERATOR_LE:       common.Le,
+		genv2.Operator_OPERATOR_GT:       common.Gt,
+		genv2.Operator_OPERATOR_GE:       common.Ge,
+		genv2.Operator_OPERATOR_MIN:      common.Min,
+		genv2.Operator_OPERATOR_MAX:      common.Max,
+		genv2.Operator_OPERATOR_FLOORDIV: common.FloorDiv,
+		genv2.Operator_OPERATOR_CEILDIV:  common.CeilDiv,
+		genv2.Operator_OPERATOR_SUM:      common.Sum,
+		genv2.Operator_OPERATOR_PRODUCT:  common.Product,
+		genv2.Operator_OPERATOR_NEG:      common.Neg,
+		genv2.Operator_OPERATOR_ABS:      common.Abs,
+		genv2.Operator_OPERATOR_SIGN:     common.Sign,
+		genv2.Operator_OPERATOR_SQRT:     common.Sqrt,
+	}
+	printOrdersV2 = map[genv2.PrintOrder]common.PrintOrder{
+		genv2.PrintOrder_PRINT_ORDER_UNSPECIFIED: "",
+		genv2.PrintOrder_PRINT_ORDER_REQUEST:     common.RequestPrintOrder,
+		genv2.PrintOrder_PRINT_ORDER_COMPLETION:  common.CompletionPrintOrder,
+	}
+	numberModesV2 = map[genv2.NumberMode]common.NumberMode{
+		genv2.NumberMode_NUMBER_MODE_UNSPECIFIED: "",
+		genv2.NumberMode_NUMBER_MODE_INT64:       common.Int64Numbers,
+		genv2.NumberMode_NUMBER_MODE_BIG:         common.BigNumbers,
+		genv2.NumberMode_NUMBER_MODE_DECIMAL:     common.DecimalNumbers,
+	}
+	overflowModesV2 = map[genv2.OverflowMode]common.OverflowMode{
+		genv2.OverflowMode_OVERFLOW_MODE_UNSPECIFIED: "",
+		genv2.OverflowMode_OVERFLOW_MODE_WRAPPING:    common.WrappingOverflow,
+		genv2.OverflowMode_OVERFLOW_MODE_CHECKED:     common.CheckedOverflow,
+		genv2.OverflowMode_OVERFLOW_MODE_SATURATING:  common.SaturatingOverflow,
+	}
+	roundingModesV2 = map[genv2.RoundingMode]common.RoundingMode{
+		genv2.RoundingMode_ROUNDING_MODE_UNSPECIFIED: "",
+		genv2.RoundingMode_ROUNDING_MODE_HALF_UP:     common.HalfUpRounding,
+		genv2.RoundingMode_ROUNDING_MODE_HALF_EVEN:   common.HalfEvenRounding,
+		genv2.RoundingMode_ROUNDING_MODE_DOWN:        common.DownRounding,
+		genv2.RoundingMode_ROUNDING_MODE_UP:          common.UpRounding,
+		genv2.RoundingMode_ROUNDING_MODE_FLOOR:       common.FloorRounding,
+		genv2.RoundingMode_ROUNDING_MODE_CEILING:     common.CeilingRounding,
+	}
+	evaluationModesV2 = map[genv2.EvaluationMode]common.EvaluationMode{
+		genv2.EvaluationMode_EVALUATION_MODE_UNSPECIFIED: "",
+		genv2.EvaluationMode_EVALUATION_MODE_EAGER:       common.EagerEvaluation,
+		genv2.EvaluationMode_EVALUATION_MODE_LAZY:        common.LazyEvaluation,
+	}
+	errorModesV2 = map[genv2.ErrorMode]common.ErrorMode{
+		genv2.ErrorMode_ERROR_MODE_UNSPECIFIED: "",
+		genv2.ErrorMode_ERROR_MODE_FAIL_FAST:   common.FailFastErrors,
+		genv2.ErrorMode_ERROR_MODE_COLLECT:     common.CollectErrors,
+	}
+)
+
+func (ca *CalculatorGRPCV2) Execute(
+	ctx context.Context,
+	request *genv2.Request,
+) (*genv2.Response, error) {
+	requestID := ctx.Value("request_id").(string)
+	ca.logger.Info("Processing GRPC v2 request with request_id", "request_id", requestID)
+	c := common.NewUpgradedCalculator(ca.logger, requestID)
+
+	var (
+		operations []common.Operation
+		err        error
+	)
+	switch source := request.GetSource().(type) {
+	case *genv2.Request_Program:
+		if operations, err = dsl.Parse(source.Program); err != nil {
+			ca.logger.Error(err.Error())
+			return nil, err
+		}
+	case *genv2.Request_Operations:
+		var invalid invalidOperationsError
+		for i, op := range source.Operations.GetOperation() {
+			validatedOp, err := ca.parseOperation(op)
+			if err != nil {
+				ca.logger.Error(err.Error(), "index", i)
+				invalid = append(invalid, locate(err, i, op.GetVar()))
+				continue
+			}
+			operations = append(operations, *validatedOp)
+		}
+		if len(invalid) > 0 {
+			return nil, invalid
+		}
+	}
+
+	opts, err := ca.parseOptions(request.GetOptions())
+	if err != nil {
+		ca.logger.Error(err.Error())
+		return nil, err
+	}
+	if opts.Inputs, err = ca.parseInputs(request.GetInputs()); err != nil {
+		ca.logger.Error(err.Error())
+		return nil, err
+	}
+	start := time.Now()
+	result, err := c.Run(ctx, operations, opts)
+	if err != nil {
+		ca.logger.Error(err.Error())
+		return nil, err
+	}
+
+	resp := &genv2.Response{
+		Summary: &genv2.Summary{
+			Computed: int64(result.Summary.Computed),
+			Skipped:  result.Summary.Skipped,
+		},
+		Metadata: &genv2.Metadata{
+			RequestId: requestID,
+			Elapsed:   durationpb.New(time.Since(start)),
+		},
+	}
+	for _, output := range result.Items {
+		resp.Items = append(resp.Items, &genv2.Variable{Var: output.Var, Value: valueV2(output.Value), Seq: output.Seq})
+	}
+	for _, opErr := range result.Errors {
+		resp.Errors = append(resp.Errors, &genv2.OperationError{
+			Index:   int32(opErr.Index),
+			Var:     opErr.Var,
+			Code:    errorCodeV2(opErr.Code),
+			Message: opErr.Message,
+		})
+	}
+	ca.logger.Info("Response formed", "request_id", requestID)
+	return resp, nil
+}
+
+func (ca *CalculatorGRPCV2) parseOperation(op *genv2.Operation) (*common.Operation, error) {
+	result := common.Operation{Var: op.GetVar()}
+	switch body := op.GetBody().(type) {
+	case *genv2.Operation_Calc:
+		result.Type = common.CalcOperation
+		symbol, ok := operatorsV2[body.Calc.GetOp()]
+		if !ok {
+			return nil, common.NewError(common.InvalidOperationCode, "invalid operator %s", body.Calc.GetOp()).WithField("calc.op")
+		}
+		result.Op = symbol
+		for i, arg := range body.Calc.GetArgs() {
+			parsed, err := ca.parseOperand(arg)
+			if err != nil {
+				return nil, withField(err, fmt.Sprintf("calc.args[%d]", i))
+			}
+			result.Args = append(result.Args, *parsed)
+		}
+	case *genv2.Operation_Print:
+		result.Type = common.PrintOperation
+	case *genv2.Operation_Expr:
+		result.Type = common.ExprOperation
+		result.Expr = body.Expr.GetExpr()
+	case *genv2.Operation_Cond:
+		result.Type = common.CondOperation
+		for _, branch := range []struct {
+			field   string
+			operand *genv2.Operand
+			target  **common.Operand
+		}{
+			{"cond.cond", body.Cond.GetCond(), &result.Cond},
+			{"cond.then", body.Cond.GetThen(), &result.Then},
+			{"cond.else", body.Cond.GetElse(), &result.Else},
+		} {
+			parsed, err := ca.parseOperand(branch.operand)
+			if err != nil {
+				return nil, withField(err, branch.field)
+			}
+			*branch.target = parsed
+		}
+	default:
+		return nil, common.NewError(common.InvalidOperationCode, "operation body cannot be empty").WithField("body")
+	}
+
+	if op.GetType() != genv2.OperationType_OPERATION_TYPE_UNSPECIFIED && operationTypesV2[op.GetType()] != result.Type {
+		return nil, common.NewError(common.InvalidOperationCode, "operation type %s does not match the %s body", op.GetType(), result.Type).WithField("type")
+	}
+	if result.Type != common.PrintOperation {
+		if err := result.CheckOperands(); err != nil {
+			return nil, err
+		}
+	}
+	return &result, nil
+}
+
+func (ca *CalculatorGRPCV2) parseOperand(op *genv2.Operand) (*common.Operand, error) {
+	switch v := op.GetValue().(type) {
+	case *genv2.Operand_Number:
+		return &common.Operand{IntValue: &v.Number}, nil
+	case *genv2.Operand_Variable:
+		if !common.IsVariableName(v.Variable) {
+			return nil, common.NewError(common.InvalidOperandCode, "invalid variable name '%s'", v.Variable)
+		}
+		return &common.Operand{StringValue: &v.Variable}, nil
+	case *genv2.Operand_BigNumber:
+		num, ok := new(big.Int).SetString(v.BigNumber, 10)
+		if !ok {
+			return nil, common.NewError(common.InvalidOperandCode, "invalid big number operand from request")
+		}
+		return &common.Operand{BigValue: num}, nil
+	case *genv2.Operand_FloatNumber:
+		if err := checkFinite(v.FloatNumber); err != nil {
+			return nil, err
+		}
+		return &common.Operand{FloatValue: &v.FloatNumber}, nil
+	case *genv2.Operand_DecimalNumber:
+		num, err := common.ParseDecimal(v.DecimalNumber)
+		if err != nil {
+			return nil, err
+		}
+		return &common.Operand{DecimalValue: &num}, nil
+	}
+	return nil, common.NewError(common.InvalidOperandCode, "operand value cannot be empty")
+}
+
+func (ca *CalculatorGRPCV2) parseInputs(inputs map[string]*genv2.Value) (map[string]common.Operand, error) {
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]common.Operand, len(inputs))
+	for name, input := range inputs {
+		var operand common.Operand
+		switch v := input.GetValue().(type) {
+		case *genv2.Value_Number:
+			operand.IntValue = &v.Number
+		case *genv2.Value_BigNumber:
+			num, ok := new(big.Int).SetString(v.BigNumber, 10)
+			if !ok {
+				return nil, common.NewError(common.InvalidOperandCode, "invalid big number input").WithField("inputs." + name)
+			}
+			operand.BigValue = num
+		case *genv2.Value_FloatNumber:
+			if err := checkFinite(v.FloatNumber); err != nil {
+				return nil, withField(err, "inputs."+name)
+			}
+			operand.FloatValue = &v.FloatNumber
+		case *genv2.Value_DecimalNumber:
+			num, err := common.ParseDecimal(v.DecimalNumber)
+			if err != nil {
+				return nil, withField(err, "inputs."+name)
+			}
+			operand.DecimalValue = &num
+		default:
+			return nil, common.NewError(common.InvalidOperandCode, "input value cannot be empty").WithField("inputs." + name)
+		}
+		result[name] = operand
+	}
+	return result, nil
+}
+
+func (ca *CalculatorGRPCV2) parseOptions(options *genv2.Options) (common.Options, error) {
+	opts := common.Options{DecimalScale: options.DecimalScale}
+	var ok bool
+	if opts.PrintOrder, ok = printOrdersV2[options.GetPrintOrder()]; !ok {
+		return opts, common.NewError(common.InvalidRequestCode, "invalid print order %s", options.GetPrintOrder()).WithField("options.print_order")
+	}
+	if opts.Numbers, ok = numberModesV2[options.GetNumbers()]; !ok {
+		return opts, common.NewError(common.InvalidRequestCode, "invalid numbers mode %s", options.GetNumbers()).WithField("options.numbers")
+	}
+	if opts.Overflow, ok = overflowModesV2[options.GetOverflow()]; !ok {
+		return opts, common.NewError(common.InvalidRequestCode, "invalid overflow mode %s", options.GetOverflow()).WithField("options.overflow")
+	}
+	if opts.DecimalRounding, ok = roundingModesV2[options.GetDecimalRounding()]; !ok {
+		return opts, common.NewError(common.InvalidRequestCode, "invalid rounding mode %s", options.GetDecimalRounding()).WithField("options.decimal_rounding")
+	}
+	if opts.Evaluation, ok = evaluationModesV2[options.GetEvaluation()]; !ok {
+		return opts, common.NewError(common.InvalidRequestCode, "invalid evaluation mode %s", options.GetEvaluation()).WithField("options.evaluation")
+	}
+	if opts.Errors, ok = errorModesV2[options.GetErrors()]; !ok {
+		return opts, common.NewError(common.InvalidRequestCode, "invalid errors mode %s", options.GetErrors()).WithField("options.errors")
+	}
+	if err := opts.Validate(); err != nil {
+		var coded *common.Error
+		if errors.As(err, &coded) && coded.Field != "" {
+			return opts, coded.WithField("options." + coded.Field)
+		}
+		return opts, err
+	}
+	return opts, nil
+}
+
+func valueV2(value common.Value) *genv2.Value {
+	switch value.Kind() {
+	case common.BigKind:
+		return &genv2.Value{Value: &genv2.Value_BigNumber{BigNumber: value.String()}}
+	case common.DecimalKind:
+		return &genv2.Value{Value: &genv2.Value_DecimalNumber{DecimalNumber: value.String()}}
+	case common.FloatKind:
+		return &genv2.Value{Value: &genv2.Value_FloatNumber{FloatNumber: value.Float64()}}
+	}
+	number, _ := value.Int64()
+	return &genv2.Value{Value: &genv2.Value_Number{Number: number}}
+}
+
+// errorCodeV2 maps an error code to the ErrorCode enum, whose value names
+// are the codes with a prefix.
+func errorCodeV2(code common.ErrorCode) genv2.ErrorCode {
+	return genv2.ErrorCode(genv2.ErrorCode_value["ERROR_CODE_"+string(code)])
+}
@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math"
+	"testing"
+	genv2 "upgraded-calculator/gen/v2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executeV2(t *testing.T, request *genv2.Request) (*genv2.Response, error) {
+	t.Helper()
+	ca := &CalculatorGRPCV2{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	ctx := context.WithValue(context.Background(), "request_id", "test")
+	return ca.Execute(ctx, request)
+}
+
+func numberV2(n int64) *genv2.Operand {
+	return &genv2.Operand{Value: &genv2.Operand_Number{Number: n}}
+}
+
+func variableV2(name string) *genv2.Operand {
+	return &genv2.Operand{Value: &genv2.Operand_Variable{Variable: name}}
+}
+
+func operationsV2(operations ...*genv2.Operation) *genv2.Request_Operations {
+	return &genv2.Request_Operations{Operations: &genv2.Operations{Operation: operations}}
+}
+
+func TestExecuteV2(t *testing.T) {
+	response, err := executeV2(t, &genv2.Request{
+		Source: operationsV2(
+			&genv2.Operation{Var: "x", Body: &genv2.Operation_Calc{Calc: &genv2.Calc{
+				Op: genv2.Operator_OPERATOR_ADD, Args: []*genv2.Operand{numberV2(1), numberV2(2)},
+			}}},
+			&genv2.Operation{Var: "y", Body: &genv2.Operation_Expr{Expr: &genv2.Expr{Expr: "x / 0"}}},
+			&genv2.Operation{Var: "z", Body: &genv2.Operation_Cond{Cond: &genv2.Cond{
+				Cond: variableV2("x"), Then: numberV2(10), Else: variableV2("y"),
+			}}},
+			&genv2.Operation{Type: genv2.OperationType_OPERATION_TYPE_PRINT, Var: "z", Body: &genv2.Operation_Print{Print: &genv2.Print{}}},
+		),
+		Options: &genv2.Options{Errors: genv2.ErrorMode_ERROR_MODE_COLLECT},
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Items, 1)
+	assert.Equal(t, "z", response.Items[0].Var)
+	assert.Equal(t, int64(10), response.Items[0].Value.GetNumber())
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, int32(1), response.Errors[0].Index)
+	assert.Equal(t, genv2.ErrorCode_ERROR_CODE_DIVISION_BY_ZERO, response.Errors[0].Code)
+	assert.Equal(t, "test", response.Metadata.RequestId)
+	assert.NotNil(t, response.Metadata.Elapsed)
+}
+
+func TestExecuteV2_InvalidOperations(t *testing.T) {
+	_, err := executeV2(t, &genv2.Request{
+		Source: operationsV2(
+			&genv2.Operation{Var: "x", Body: &genv2.Operation_Calc{Calc: &genv2.Calc{Op: genv2.Operator(100)}}},
+			&genv2.Operation{Type: genv2.OperationType_OPERATION_TYPE_CALC, Var: "x", Body: &genv2.Operation_Print{Print: &genv2.Print{}}},
+			&genv2.Operation{Var: "y"},
+			&genv2.Operation{Var: "z", Body: &genv2.Operation_Cond{Cond: &genv2.Cond{Cond: numberV2(1), Then: numberV2(1)}}},
+			&genv2.Operation{Var: "w", Body: &genv2.Operation_Calc{Calc: &genv2.Calc{
+				Op: genv2.Operator_OPERATOR_ADD, Args: []*genv2.Operand{numberV2(1), {Value: &genv2.Operand_FloatNumber{FloatNumber: math.Inf(1)}}},
+			}}},
+			&genv2.Operation{Var: "v", Body: &genv2.Operation_Cond{Cond: &genv2.Cond{
+				Cond: numberV2(1), Then: variableV2("x$0.1"), Else: numberV2(0),
+			}}},
+		),
+	})
+	var invalid invalidOperationsError
+	require.ErrorAs(t, err, &invalid)
+	var fields []string
+	for _, operationErr := range invalid {
+		fields = append(fields, operationErr.Field)
+	}
+	assert.Equal(t, []string{"calc.op", "type", "body", "cond.else", "calc.args[1]", "cond.then"}, fields)
+
+	_, err = executeV2(t, &genv2.Request{Options: &genv2.Options{Numbers: genv2.NumberMode(7)}})
+	assert.EqualError(t, err, "invalid numbers mode 7")
+}
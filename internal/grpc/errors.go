@@ -0,0 +1,159 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/dsl"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the errdetails.ErrorInfo attached to errors.
+const errorDomain = "upgraded-calculator"
+
+// grpcCode maps error codes to gRPC status codes.
+func grpcCode(code common.ErrorCode) codes.Code {
+	switch code {
+	case common.InvalidRequestCode, common.SyntaxErrorCode, common.InvalidOperationCode,
+		common.InvalidOperandCode, common.UndefinedVariableCode, common.DuplicateAssignmentCode,
+		common.CycleCode, common.DivisionByZeroCode, common.UpstreamFailedCode, common.EvaluationCode:
+		return codes.InvalidArgument
+	case common.OverflowCode:
+		return codes.OutOfRange
+	case common.TimeoutCode:
+		return codes.DeadlineExceeded
+	case common.CancelledCode:
+		return codes.Canceled
+	case common.NotFoundCode:
+		return codes.NotFound
+	case common.LimitExceededCode:
+		return codes.ResourceExhausted
+	}
+	return codes.Internal
+}
+
+// statusError converts the error into a gRPC status carrying an
+// errdetails.ErrorInfo with the error code as the reason and, for invalid
+// requests, an errdetails.BadRequest pointing at the field at fault.
+func statusError(err error) error {
+	code := common.CodeOf(err)
+	message := err.Error()
+	if code == common.InternalCode {
+		message = "internal server error"
+	}
+	st := status.New(grpcCode(code), message)
+
+	info := &errdetails.ErrorInfo{Reason: string(code), Domain: errorDomain}
+	badRequest := &errdetails.BadRequest{}
+	var located *common.Error
+	if errors.As(err, &located) {
+		info.Metadata = make(map[string]string)
+		field := located.Field
+		if located.Index != nil {
+			info.Metadata["index"] = strconv.Itoa(*located.Index)
+			field = fmt.Sprintf("operation[%d]", *located.Index)
+			if located.Field != "" {
+				field += "." + located.Field
+			}
+		}
+		if located.Var != "" {
+			info.Metadata["var"] = located.Var
+		}
+		if field != "" && grpcCode(code) == codes.InvalidArgument {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: located.Message,
+			})
+		}
+	}
+	var invalid invalidOperationsError
+	if errors.As(err, &invalid) {
+		info.Metadata = map[string]string{"index": strconv.Itoa(*invalid[0].Index), "var": invalid[0].Var}
+		for _, operationErr := range invalid {
+			field := fmt.Sprintf("operation[%d]", *operationErr.Index)
+			if operationErr.Field != "" {
+				field += "." + operationErr.Field
+			}
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: operationErr.Message,
+			})
+		}
+	}
+	var diagnostics dsl.ErrorList
+	if errors.As(err, &diagnostics) {
+		for _, diagnostic := range diagnostics {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       "program",
+				Description: diagnostic.Error(),
+			})
+		}
+	}
+
+	if len(badRequest.FieldViolations) > 0 {
+		if detailed, detailsErr := st.WithDetails(info, badRequest); detailsErr == nil {
+			return detailed.Err()
+		}
+	} else if detailed, detailsErr := st.WithDetails(info); detailsErr == nil {
+		return detailed.Err()
+	}
+	return st.Err()
+}
+
+// Validation modes of the operations of a request.
+const (
+	// strictValidation rejects the request if any operation is invalid.
+	strictValidation = "strict"
+	// lenientValidation drops invalid operations, executes the rest and
+	// lists the dropped ones in the response.
+	lenientValidation = "lenient"
+)
+
+// invalidOperationsError reports every operation of the request that
+// failed validation.
+type invalidOperationsError []*common.Error
+
+func (e invalidOperationsError) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = fmt.Sprintf("operation %d: %s", *err.Index, err.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e invalidOperationsError) ErrorCode() common.ErrorCode {
+	return e[0].Code
+}
+
+// locate locates the validation error of the operation at index.
+func locate(err error, index int, name string) *common.Error {
+	var coded *common.Error
+	if !errors.As(err, &coded) {
+		coded = common.NewError(common.InvalidOperationCode, "%w", err)
+	}
+	return coded.AtOperation(index, name)
+}
+
+// withField names the field at fault unless the error already does.
+func withField(err error, field string) error {
+	var coded *common.Error
+	if errors.As(err, &coded) && coded.Field == "" {
+		return coded.WithField(field)
+	}
+	return err
+}
+
+// relocate translates the operation an error is located at from the
+// executed operations to the request, kept maps one to the other.
+func relocate(err error, kept []int) error {
+	var located *common.Error
+	if kept == nil || !errors.As(err, &located) || located.Index == nil {
+		return err
+	}
+	return located.AtOperation(kept[*located.Index], located.Var)
+}
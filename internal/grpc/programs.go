@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"upgraded-calculator/gen"
+	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/programs"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SaveProgram validates the operations of the request like Execute does
+// and stores them as a new version of the program.
+func (ca *CalculatorGRPC) SaveProgram(ctx context.Context, request *gen.SaveProgramRequest) (*gen.ProgramVersion, error) {
+	prepared, err := ca.prepare(&gen.Request{Operation: request.GetOperation(), Program: request.Program})
+	if err != nil {
+		return nil, err
+	}
+	version, err := ca.programs.Save(request.GetName(), request.GetInputs(), prepared.operations)
+	if err != nil {
+		ca.logger.Error(err.Error())
+		return nil, err
+	}
+	return programVersion(version), nil
+}
+
+// ListProgramVersions describes the versions of the named program.
+func (ca *CalculatorGRPC) ListProgramVersions(ctx context.Context, name string) (*gen.ListProgramVersionsResponse, error) {
+	versions, err := ca.programs.Versions(name)
+	if err != nil {
+		return nil, err
+	}
+	response := &gen.ListProgramVersionsResponse{}
+	for _, version := range versions {
+		response.Versions = append(response.Versions, programVersion(version))
+	}
+	return response, nil
+}
+
+// ExecuteSavedProgram runs a version of a saved program with the options
+// and inputs of the request.
+func (ca *CalculatorGRPC) ExecuteSavedProgram(
+	ctx context.Context,
+	name string,
+	version int,
+	request *gen.Request,
+) (*gen.Response, error) {
+	ca.logger.Info("Processing GRPC saved program request with request_id", "request_id", ctx.Value("request_id"), "name", name, "version", version)
+	if request == nil {
+		request = &gen.Request{}
+	}
+	if len(request.GetOperation()) > 0 || request.Program != nil {
+		return nil, common.NewError(common.InvalidRequestCode, "operations of a saved program cannot be replaced").WithField("request.operation")
+	}
+	prepared, err := ca.prepare(request)
+	if err != nil {
+		return nil, err
+	}
+	result, err := ca.programs.Execute(ctx, name, version, ctx.Value("request_id").(string), prepared.opts)
+	if err != nil {
+		ca.logger.Error(err.Error())
+		return nil, err
+	}
+	return ca.formResult(prepared, result), nil
+}
+
+func programVersion(version programs.Version) *gen.ProgramVersion {
+	return &gen.ProgramVersion{
+		Name:      version.Name,
+		Version:   int32(version.Version),
+		Inputs:    version.Inputs,
+		CreatedAt: timestamppb.New(version.CreatedAt),
+	}
+}
@@ -7,7 +7,10 @@ import (
 	"google.golang.org/grpc/keepalive"
 	"log/slog"
 	"upgraded-calculator/gen"
+	genv2 "upgraded-calculator/gen/v2"
 	"upgraded-calculator/internal/config"
+	"upgraded-calculator/internal/programs"
+	"upgraded-calculator/internal/sessions"
 )
 
 type serverAPI struct {
@@ -20,29 +23,186 @@ type Calculator interface {
 		ctx context.Context,
 		request *gen.Request,
 	) (response *gen.Response, err error)
+	ExecuteStream(
+		ctx context.Context,
+		request *gen.Request,
+		send func(*gen.Event) error,
+	) error
+	Session(
+		ctx context.Context,
+		recv func() (*gen.Operation, error),
+		send func(*gen.Event) error,
+	) error
+	CreateSession(ctx context.Context) (*gen.SessionInfo, error)
+	GetSession(ctx context.Context, id string) (*gen.SessionInfo, error)
+	ExecuteInSession(
+		ctx context.Context,
+		id string,
+		request *gen.Request,
+	) (*gen.Response, error)
+	DeleteSession(ctx context.Context, id string) error
+	SaveProgram(ctx context.Context, request *gen.SaveProgramRequest) (*gen.ProgramVersion, error)
+	ListProgramVersions(ctx context.Context, name string) (*gen.ListProgramVersionsResponse, error)
+	ExecuteSavedProgram(
+		ctx context.Context,
+		name string,
+		version int,
+		request *gen.Request,
+	) (*gen.Response, error)
+}
+
+type serverAPIV2 struct {
+	genv2.UnimplementedCalculatorServer
+	calculator CalculatorV2
+}
+
+type CalculatorV2 interface {
+	Execute(
+		ctx context.Context,
+		request *genv2.Request,
+	) (response *genv2.Response, err error)
 }
 
 func RegisterGRPCServer(server *grpc.Server, calculator Calculator) {
 	gen.RegisterCalculatorServer(server, &serverAPI{calculator: calculator})
 }
 
+func RegisterGRPCServerV2(server *grpc.Server, calculator CalculatorV2) {
+	genv2.RegisterCalculatorServer(server, &serverAPIV2{calculator: calculator})
+}
+
 func (s *serverAPI) Execute(
 	ctx context.Context,
 	request *gen.Request,
 ) (response *gen.Response, err error) {
 	ctx = context.WithValue(ctx, "request_id", uuid.New().String())
 	resp, err := s.calculator.Execute(ctx, request)
-	return resp, err
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return resp, nil
+}
+
+func (s *serverAPI) ExecuteStream(
+	request *gen.Request,
+	stream grpc.ServerStreamingServer[gen.Event],
+) error {
+	ctx := context.WithValue(stream.Context(), "request_id", uuid.New().String())
+	if err := s.calculator.ExecuteStream(ctx, request, stream.Send); err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+func (s *serverAPI) Session(stream grpc.BidiStreamingServer[gen.Operation, gen.Event]) error {
+	ctx := context.WithValue(stream.Context(), "request_id", uuid.New().String())
+	if err := s.calculator.Session(ctx, stream.Recv, stream.Send); err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+func (s *serverAPI) CreateSession(
+	ctx context.Context,
+	request *gen.CreateSessionRequest,
+) (*gen.SessionInfo, error) {
+	info, err := s.calculator.CreateSession(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return info, nil
+}
+
+func (s *serverAPI) GetSession(
+	ctx context.Context,
+	request *gen.GetSessionRequest,
+) (*gen.SessionInfo, error) {
+	info, err := s.calculator.GetSession(ctx, request.GetSessionId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return info, nil
+}
+
+func (s *serverAPI) ExecuteInSession(
+	ctx context.Context,
+	request *gen.ExecuteInSessionRequest,
+) (*gen.Response, error) {
+	ctx = context.WithValue(ctx, "request_id", uuid.New().String())
+	resp, err := s.calculator.ExecuteInSession(ctx, request.GetSessionId(), request.GetRequest())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return resp, nil
+}
+
+func (s *serverAPI) DeleteSession(
+	ctx context.Context,
+	request *gen.DeleteSessionRequest,
+) (*gen.DeleteSessionResponse, error) {
+	if err := s.calculator.DeleteSession(ctx, request.GetSessionId()); err != nil {
+		return nil, statusError(err)
+	}
+	return &gen.DeleteSessionResponse{}, nil
+}
+
+func (s *serverAPI) SaveProgram(
+	ctx context.Context,
+	request *gen.SaveProgramRequest,
+) (*gen.ProgramVersion, error) {
+	version, err := s.calculator.SaveProgram(ctx, request)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return version, nil
+}
+
+func (s *serverAPI) ListProgramVersions(
+	ctx context.Context,
+	request *gen.ListProgramVersionsRequest,
+) (*gen.ListProgramVersionsResponse, error) {
+	versions, err := s.calculator.ListProgramVersions(ctx, request.GetName())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return versions, nil
+}
+
+func (s *serverAPI) ExecuteSavedProgram(
+	ctx context.Context,
+	request *gen.ExecuteSavedProgramRequest,
+) (*gen.Response, error) {
+	ctx = context.WithValue(ctx, "request_id", uuid.New().String())
+	resp, err := s.calculator.ExecuteSavedProgram(ctx, request.GetName(), int(request.GetVersion()), request.GetRequest())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return resp, nil
+}
+
+func (s *serverAPIV2) Execute(
+	ctx context.Context,
+	request *genv2.Request,
+) (response *genv2.Response, err error) {
+	ctx = context.WithValue(ctx, "request_id", uuid.New().String())
+	resp, err := s.calculator.Execute(ctx, request)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return resp, nil
 }
 
 func CreateServer(
 	config *config.Config,
 	logger *slog.Logger,
+	sessionManager *sessions.Manager,
+	library *programs.Library,
 ) *grpc.Server {
-	calculator := &CalculatorGRPC{logger: logger}
+	calculator := &CalculatorGRPC{logger: logger, sessions: sessionManager, programs: library}
 
 	grpcServer := grpc.NewServer(grpc.KeepaliveParams(keepalive.ServerParameters{Timeout: config.App.GRPCTimeout}))
 	RegisterGRPCServer(grpcServer, calculator)
+	RegisterGRPCServerV2(grpcServer, &CalculatorGRPCV2{logger: logger})
 
 	return grpcServer
 }
@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"sort"
+	"upgraded-calculator/gen"
+	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/sessions"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CreateSession starts a session and describes it.
+func (ca *CalculatorGRPC) CreateSession(ctx context.Context) (*gen.SessionInfo, error) {
+	info, err := ca.sessions.Create()
+	if err != nil {
+		ca.logger.Error(err.Error())
+		return nil, err
+	}
+	return sessionInfo(info), nil
+}
+
+// GetSession describes the session together with its variables.
+func (ca *CalculatorGRPC) GetSession(ctx context.Context, id string) (*gen.SessionInfo, error) {
+	info, err := ca.sessions.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return sessionInfo(info), nil
+}
+
+// ExecuteInSession runs the request like Execute with the variables of the
+// session available.
+func (ca *CalculatorGRPC) ExecuteInSession(
+	ctx context.Context,
+	id string,
+	request *gen.Request,
+) (*gen.Response, error) {
+	ca.logger.Info("Processing GRPC session request with request_id", "request_id", ctx.Value("request_id"), "session_id", id)
+	prepared, err := ca.prepare(request)
+	if err != nil {
+		return nil, err
+	}
+	result, err := ca.sessions.Execute(ctx, id, ctx.Value("request_id").(string), prepared.operations, prepared.opts)
+	if err != nil {
+		ca.logger.Error(err.Error())
+		return nil, relocate(err, prepared.kept)
+	}
+	return ca.formResult(prepared, result), nil
+}
+
+// DeleteSession ends the session.
+func (ca *CalculatorGRPC) DeleteSession(ctx context.Context, id string) error {
+	return ca.sessions.Delete(id)
+}
+
+func sessionInfo(info sessions.Info) *gen.SessionInfo {
+	result := &gen.SessionInfo{
+		Id:        info.ID,
+		CreatedAt: timestamppb.New(info.CreatedAt),
+		ExpiresAt: timestamppb.New(info.ExpiresAt),
+	}
+	names := make([]string, 0, len(info.Variables))
+	for name := range info.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		result.Variables = append(result.Variables, formVariable(common.PrintOutput{Var: name, Value: info.Variables[name]}))
+	}
+	return result
+}
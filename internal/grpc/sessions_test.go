@@ -0,0 +1,46 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"upgraded-calculator/gen"
+	"upgraded-calculator/internal/config"
+	"upgraded-calculator/internal/sessions"
+	"upgraded-calculator/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteInSession(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	manager := sessions.NewManager(&config.Config{App: config.AppConfig{
+		SessionTTL:          60,
+		MaxSessions:         1,
+		MaxSessionVariables: 10,
+	}}, logger, storage.NewMemoryStore())
+	ca := &CalculatorGRPC{logger: logger, sessions: manager}
+	ctx := context.WithValue(context.Background(), "request_id", "test")
+
+	info, err := ca.CreateSession(ctx)
+	require.NoError(t, err)
+
+	// A request left out of the message is an empty one.
+	response, err := ca.ExecuteInSession(ctx, info.Id, nil)
+	require.NoError(t, err)
+	assert.Empty(t, response.Items)
+
+	add := "+"
+	_, err = ca.ExecuteInSession(ctx, info.Id, &gen.Request{Operation: []*gen.Operation{
+		{Type: "calc", Op: &add, Var: "x", Left: number(1), Right: number(2)},
+	}})
+	require.NoError(t, err)
+	response, err = ca.ExecuteInSession(ctx, info.Id, &gen.Request{Operation: []*gen.Operation{
+		{Type: "print", Var: "x"},
+	}})
+	require.NoError(t, err)
+	require.Len(t, response.Items, 1)
+	assert.Equal(t, int64(3), response.Items[0].Value)
+}
@@ -5,10 +5,15 @@ import (
 	"encoding/json"
 	"log/slog"
 	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/dsl"
+	"upgraded-calculator/internal/programs"
+	"upgraded-calculator/internal/sessions"
 )
 
 type CalculatorHTTP struct {
-	logger *slog.Logger
+	logger   *slog.Logger
+	sessions *sessions.Manager
+	programs *programs.Library
 }
 
 func (ca *CalculatorHTTP) Execute(
@@ -16,23 +21,118 @@ func (ca *CalculatorHTTP) Execute(
 	data []byte,
 ) ([]byte, error) {
 	ca.logger.Info("Processing HTTP request with request_id", "request_id", ctx.Value("request_id"))
+	req, err := ca.decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return ca.execute(ctx, req)
+}
+
+// ExecuteProgram runs a program written in the text format of the dsl
+// package with default options.
+func (ca *CalculatorHTTP) ExecuteProgram(
+	ctx context.Context,
+	data []byte,
+) ([]byte, error) {
+	ca.logger.Info("Processing HTTP program request with request_id", "request_id", ctx.Value("request_id"))
+	req, err := ca.decodeProgram(data)
+	if err != nil {
+		return nil, err
+	}
+	return ca.execute(ctx, req)
+}
+
+// ExecuteStream runs the request like execute and passes every print and
+// every failed operation to emit as soon as the calculator reports them,
+// followed by the summary. A failed emit cancels the execution.
+func (ca *CalculatorHTTP) ExecuteStream(
+	ctx context.Context,
+	req common.Request,
+	emit func(event string, data any) error,
+) error {
+	ca.logger.Info("Processing HTTP stream with request_id", "request_id", ctx.Value("request_id"))
 	c := common.NewUpgradedCalculator(ca.logger, ctx.Value("request_id").(string))
-	var req []common.Operation
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var emitErr error
+	c.Observe(func(event common.Event) {
+		if emitErr != nil {
+			return
+		}
+		switch {
+		case event.Print != nil:
+			emitErr = emit(printEvent, event.Print)
+		case event.Error != nil:
+			emitErr = emit(errorEvent, event.Error)
+		}
+		if emitErr != nil {
+			ca.logger.Error(emitErr.Error())
+			cancel()
+		}
+	})
+	result, err := c.Run(ctx, req.Operations, req.Options)
+	if emitErr != nil {
+		return emitErr
+	}
+	if err != nil {
+		ca.logger.Error(err.Error())
+		return err
+	}
+	ca.logger.Info("Stream finished")
+	return emit(summaryEvent, result.Summary)
+}
+
+// decode decodes a JSON request body.
+func (ca *CalculatorHTTP) decode(data []byte) (common.Request, error) {
+	var req common.Request
 	err := json.Unmarshal(data, &req)
 	if err != nil {
 		ca.logger.Error(err.Error())
-		return nil, err
+		if common.CodeOf(err) == common.InternalCode {
+			err = common.NewError(common.InvalidRequestCode, "invalid request body: %w", err)
+		}
+		return common.Request{}, err
 	}
+	return req, nil
+}
 
-	result, err := c.Execute(ctx, req)
+// decodeProgram decodes a text/plain request body.
+func (ca *CalculatorHTTP) decodeProgram(data []byte) (common.Request, error) {
+	operations, err := dsl.Parse(string(data))
+	if err != nil {
+		ca.logger.Error(err.Error())
+		return common.Request{}, err
+	}
+	return common.Request{Operations: operations}, nil
+}
+
+func (ca *CalculatorHTTP) execute(
+	ctx context.Context,
+	req common.Request,
+) ([]byte, error) {
+	c := common.NewUpgradedCalculator(ca.logger, ctx.Value("request_id").(string))
+	result, err := c.Run(ctx, req.Operations, req.Options)
 	if err != nil {
 		ca.logger.Error(err.Error())
 		return nil, err
 	}
 
 	ca.logger.Info("Request finished")
-	c = nil
-	formedResponse, err := json.Marshal(result)
+	return ca.respond(req, result)
+}
+
+// respond encodes the result in the format of the request: a bare list of
+// prints for the bare list format and a Response object otherwise.
+func (ca *CalculatorHTTP) respond(req common.Request, result common.Result) ([]byte, error) {
+	var response any = result.Items
+	if !req.Legacy() {
+		if result.Items == nil {
+			result.Items = []common.PrintOutput{}
+		}
+		response = common.Response{Items: result.Items, Summary: &result.Summary, Errors: result.Errors}
+	}
+	formedResponse, err := json.Marshal(response)
 	if err != nil {
 		ca.logger.Error(err.Error())
 		return nil, err
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/dsl"
+)
+
+// statusClientClosedRequest is the non-standard status of requests the
+// client gave up on before they were finished.
+const statusClientClosedRequest = 499
+
+// problem is an RFC 9457 problem details body extended with the error code
+// and the location of the failed operation.
+type problem struct {
+	Type        string           `json:"type"`
+	Title       string           `json:"title"`
+	Status      int              `json:"status"`
+	Detail      string           `json:"detail"`
+	Code        common.ErrorCode `json:"code"`
+	Index       *int             `json:"index,omitempty"`
+	Var         string           `json:"var,omitempty"`
+	Field       string           `json:"field,omitempty"`
+	Diagnostics []*dsl.Error     `json:"diagnostics,omitempty"`
+}
+
+// httpStatus maps error codes to HTTP statuses: malformed requests are 400,
+// programs that are well-formed but cannot be computed are 422.
+func httpStatus(code common.ErrorCode) int {
+	switch code {
+	case common.InvalidRequestCode, common.SyntaxErrorCode:
+		return http.StatusBadRequest
+	case common.InvalidOperationCode, common.InvalidOperandCode, common.UndefinedVariableCode,
+		common.DuplicateAssignmentCode, common.CycleCode, common.DivisionByZeroCode,
+		common.OverflowCode, common.UpstreamFailedCode, common.EvaluationCode:
+		return http.StatusUnprocessableEntity
+	case common.TimeoutCode:
+		return http.StatusGatewayTimeout
+	case common.CancelledCode:
+		return statusClientClosedRequest
+	case common.NotFoundCode:
+		return http.StatusNotFound
+	case common.LimitExceededCode:
+		return http.StatusTooManyRequests
+	}
+	return http.StatusInternalServerError
+}
+
+func newProblem(err error) problem {
+	code := common.CodeOf(err)
+	p := problem{
+		Type:   "urn:upgraded-calculator:error:" + strings.ToLower(string(code)),
+		Title:  strings.ReplaceAll(strings.ToLower(string(code)), "_", " "),
+		Status: httpStatus(code),
+		Detail: err.Error(),
+		Code:   code,
+	}
+	if code == common.InternalCode {
+		p.Detail = "internal server error"
+	}
+
+	var located *common.Error
+	if errors.As(err, &located) {
+		p.Index, p.Var, p.Field = located.Index, located.Var, located.Field
+	}
+	var diagnostics dsl.ErrorList
+	if errors.As(err, &diagnostics) {
+		p.Diagnostics = diagnostics
+	}
+	return p
+}
+
+// writeProblem responds with the problem details of the error.
+func writeProblem(w http.ResponseWriter, err error) {
+	writeProblemDetails(w, newProblem(err))
+}
+
+func writeProblemDetails(w http.ResponseWriter, p problem) {
+	body, _ := json.Marshal(p)
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	w.Write(body)
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/dsl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteProblem(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "located",
+			err:  common.NewError(common.DivisionByZeroCode, "division by zero").AtOperation(3, "x"),
+			want: `{
+				"type": "urn:upgraded-calculator:error:division_by_zero",
+				"title": "division by zero",
+				"status": 422,
+				"detail": "division by zero",
+				"code": "DIVISION_BY_ZERO",
+				"index": 3,
+				"var": "x"
+			}`,
+		},
+		{
+			name: "diagnostics",
+			err:  dsl.ErrorList{{Line: 2, Column: 5, Msg: "unexpected end of expression"}},
+			want: `{
+				"type": "urn:upgraded-calculator:error:syntax_error",
+				"title": "syntax error",
+				"status": 400,
+				"detail": "line 2, column 5: unexpected end of expression",
+				"code": "SYNTAX_ERROR",
+				"diagnostics": [{"line": 2, "column": 5, "message": "unexpected end of expression"}]
+			}`,
+		},
+		{
+			name: "timeout",
+			err:  context.DeadlineExceeded,
+			want: `{
+				"type": "urn:upgraded-calculator:error:timeout",
+				"title": "timeout",
+				"status": 504,
+				"detail": "context deadline exceeded",
+				"code": "TIMEOUT"
+			}`,
+		},
+		{
+			name: "internal",
+			err:  errors.New("secret details"),
+			want: `{
+				"type": "urn:upgraded-calculator:error:internal",
+				"title": "internal",
+				"status": 500,
+				"detail": "internal server error",
+				"code": "INTERNAL"
+			}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			writeProblem(recorder, tt.err)
+
+			var p problem
+			assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &p))
+			assert.Equal(t, p.Status, recorder.Code)
+			assert.Equal(t, "application/problem+json", recorder.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.want, recorder.Body.String())
+		})
+	}
+
+	assert.Equal(t, http.StatusBadRequest, httpStatus(common.InvalidRequestCode))
+}
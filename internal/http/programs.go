@@ -0,0 +1,88 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/dsl"
+)
+
+// saveProgramRequest is the body of POST /programs. The program is given
+// either as operations or in the text format.
+type saveProgramRequest struct {
+	Name       string             `json:"name"`
+	Inputs     []string           `json:"inputs"`
+	Operations []common.Operation `json:"operations"`
+	Program    *string            `json:"program"`
+}
+
+// SaveProgram stores the program of the request body as a new version.
+func (ca *CalculatorHTTP) SaveProgram(data []byte) ([]byte, error) {
+	var req saveProgramRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		ca.logger.Error(err.Error())
+		if common.CodeOf(err) == common.InternalCode {
+			err = common.NewError(common.InvalidRequestCode, "invalid request body: %w", err)
+		}
+		return nil, err
+	}
+	if req.Program != nil {
+		if len(req.Operations) > 0 {
+			return nil, common.NewError(common.InvalidRequestCode, "operations and program cannot be used together").WithField("program")
+		}
+		operations, err := dsl.Parse(*req.Program)
+		if err != nil {
+			ca.logger.Error(err.Error())
+			return nil, err
+		}
+		req.Operations = operations
+	}
+	version, err := ca.programs.Save(req.Name, req.Inputs, req.Operations)
+	if err != nil {
+		ca.logger.Error(err.Error())
+		return nil, err
+	}
+	return json.Marshal(version)
+}
+
+// ProgramVersions lists the versions of the named program.
+func (ca *CalculatorHTTP) ProgramVersions(name string) ([]byte, error) {
+	versions, err := ca.programs.Versions(name)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(versions)
+}
+
+// ExecuteSavedProgram runs a version of a saved program with the options and
+// inputs of the request body, an object like the body of /execute without
+// operations. An empty body runs it with default options.
+func (ca *CalculatorHTTP) ExecuteSavedProgram(
+	ctx context.Context,
+	name string,
+	version string,
+	data []byte,
+) ([]byte, error) {
+	ca.logger.Info("Processing HTTP saved program request with request_id", "request_id", ctx.Value("request_id"), "name", name, "version", version)
+	number, err := strconv.Atoi(version)
+	if err != nil {
+		return nil, common.NewError(common.NotFoundCode, "version %s of program '%s' does not exist", version, name)
+	}
+	req, err := ca.decode(data)
+	if len(data) == 0 {
+		req, err = common.Request{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if req.Legacy() || len(req.Operations) > 0 {
+		return nil, common.NewError(common.InvalidRequestCode, "operations of a saved program cannot be replaced").WithField("operations")
+	}
+	result, err := ca.programs.Execute(ctx, name, number, ctx.Value("request_id").(string), req.Options)
+	if err != nil {
+		ca.logger.Error(err.Error())
+		return nil, err
+	}
+	return ca.respond(req, result)
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"upgraded-calculator/internal/config"
+	"upgraded-calculator/internal/programs"
+	"upgraded-calculator/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedPrograms(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := CreateServer(&config.Config{}, logger, context.Background(), nil, programs.NewLibrary(logger, storage.NewMemoryStore())).Handler
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return recorder
+	}
+
+	response := do(http.MethodPost, "/programs", `{"name": "area", "program": "s = w * h\nprint s"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.Contains(t, response.Body.String(), `"code":"UNDEFINED_VARIABLE"`)
+
+	response = do(http.MethodPost, "/programs", `{"name": "area", "inputs": ["w", "h"], "program": "s = w * h\nprint s"}`)
+	require.Equal(t, http.StatusCreated, response.Code)
+	assert.Contains(t, response.Body.String(), `"version":1`)
+
+	response = do(http.MethodGet, "/programs/area/versions", "")
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `"name":"area","version":1,"inputs":["w","h"]`)
+
+	response = do(http.MethodPost, "/programs/area/versions/1/execute", `{"inputs": {"w": 3, "h": 4}}`)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"items": [{"var": "s", "value": 12}], "summary": {"computed": 1, "skipped": []}}`, response.Body.String())
+
+	response = do(http.MethodPost, "/programs/area/versions/1/execute", `{"inputs": {"w": 3}}`)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	response = do(http.MethodPost, "/programs/area/versions/2/execute", "")
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
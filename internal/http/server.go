@@ -2,49 +2,205 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"io"
 	"log/slog"
+	"mime"
 	"net/http"
+	"upgraded-calculator/internal/common"
 	"upgraded-calculator/internal/config"
+	"upgraded-calculator/internal/programs"
+	"upgraded-calculator/internal/sessions"
 )
 
+// maxRequestBodySize limits the size of request bodies.
+const maxRequestBodySize = 10 << 20
+
+// readBody reads the whole request body. If the body cannot be read, it
+// responds with 400, or with 413 if the body is over maxRequestBodySize, and
+// returns false.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	if err == nil {
+		return body, true
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		p := newProblem(common.NewError(common.InvalidRequestCode, "request body is larger than %d bytes", tooLarge.Limit))
+		p.Status = http.StatusRequestEntityTooLarge
+		writeProblemDetails(w, p)
+		return nil, false
+	}
+	writeProblem(w, common.NewError(common.InvalidRequestCode, "cannot read the request body: %v", err))
+	return nil, false
+}
+
 func CreateServer(
 	config *config.Config,
 	logger *slog.Logger,
 	ctx context.Context,
+	sessionManager *sessions.Manager,
+	library *programs.Library,
 ) *http.Server {
 
-	calculator := CalculatorHTTP{logger: logger}
+	calculator := CalculatorHTTP{logger: logger, sessions: sessionManager, programs: library}
 
 	// Initializing router
 	router := chi.NewRouter()
 	router.Use(middleware.Logger)
 	router.Post("/execute", func(w http.ResponseWriter, r *http.Request) {
-		bodyInBytes, err := io.ReadAll(r.Body)
+		bodyInBytes, ok := readBody(w, r)
+		if !ok {
+			return
+		}
+
+		ctx := context.WithValue(ctx, "request_id", uuid.New().String())
 
-		ctx = context.WithValue(ctx, "request_id", uuid.New().String())
+		program := false
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "text/plain" {
+			program = true
+		}
+		if mediaType := streamingMediaType(r.Header.Get("Accept")); mediaType != "" {
+			stream := &eventStream{w: w, mediaType: mediaType}
+			decode := calculator.decode
+			if program {
+				decode = calculator.decodeProgram
+			}
+			req, err := decode(bodyInBytes)
+			if err == nil {
+				err = calculator.ExecuteStream(ctx, req, stream.emit)
+			}
+			if err != nil {
+				stream.fail(err)
+			}
+			return
+		}
 
-		response, err := calculator.Execute(ctx, bodyInBytes)
+		execute := calculator.Execute
+		if program {
+			execute = calculator.ExecuteProgram
+		}
+		response, err := execute(ctx, bodyInBytes)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(err.Error()))
+			writeProblem(w, err)
 			return
 		}
+		w.Header().Set("Content-Type", "application/json")
 		w.Write(response)
 	})
 
+	writeJSON := func(w http.ResponseWriter, status int, response []byte) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(response)
+	}
+	router.Post("/sessions", func(w http.ResponseWriter, r *http.Request) {
+		response, err := calculator.CreateSession()
+		if err != nil {
+			writeProblem(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, response)
+	})
+	router.Get("/sessions/{id}", func(w http.ResponseWriter, r *http.Request) {
+		response, err := calculator.GetSession(chi.URLParam(r, "id"))
+		if err != nil {
+			writeProblem(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, response)
+	})
+	router.Post("/sessions/{id}/execute", func(w http.ResponseWriter, r *http.Request) {
+		bodyInBytes, ok := readBody(w, r)
+		if !ok {
+			return
+		}
+		ctx := context.WithValue(ctx, "request_id", uuid.New().String())
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		response, err := calculator.ExecuteInSession(ctx, chi.URLParam(r, "id"), bodyInBytes, mediaType == "text/plain")
+		if err != nil {
+			writeProblem(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, response)
+	})
+	router.Delete("/sessions/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if err := calculator.DeleteSession(chi.URLParam(r, "id")); err != nil {
+			writeProblem(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	router.Post("/programs", func(w http.ResponseWriter, r *http.Request) {
+		bodyInBytes, ok := readBody(w, r)
+		if !ok {
+			return
+		}
+		response, err := calculator.SaveProgram(bodyInBytes)
+		if err != nil {
+			writeProblem(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, response)
+	})
+	router.Get("/programs/{name}/versions", func(w http.ResponseWriter, r *http.Request) {
+		response, err := calculator.ProgramVersions(chi.URLParam(r, "name"))
+		if err != nil {
+			writeProblem(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, response)
+	})
+	router.Post("/programs/{name}/versions/{version}/execute", func(w http.ResponseWriter, r *http.Request) {
+		bodyInBytes, ok := readBody(w, r)
+		if !ok {
+			return
+		}
+		ctx := context.WithValue(ctx, "request_id", uuid.New().String())
+		response, err := calculator.ExecuteSavedProgram(ctx, chi.URLParam(r, "name"), chi.URLParam(r, "version"), bodyInBytes)
+		if err != nil {
+			writeProblem(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, response)
+	})
+
+	upgrader := websocket.Upgrader{}
+	router.Get("/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+		defer conn.Close()
+		conn.SetReadLimit(maxSessionMessageSize)
+
+		ctx := context.WithValue(ctx, "request_id", uuid.New().String())
+		recv := func() ([]byte, error) {
+			_, data, err := conn.ReadMessage()
+			return data, err
+		}
+		emit := func(event string, data any) error {
+			return conn.WriteJSON(map[string]any{event: data})
+		}
+		err = calculator.Session(ctx, recv, emit)
+		if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+			logger.Error(err.Error())
+		}
+	})
+
 	router.Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("/swagger.json"),
 	))
 
-	router.Get("/swagger.json", func(w http.ResponseWriter, r *http.Request) {
-		http.ServeFile(w, r, config.App.SwaggerPath)
-	})
+	router.Get("/swagger.json", swaggerHandler(config.App.SwaggerPath, logger))
 
 	// Creating server instance
 	server := &http.Server{Addr: fmt.Sprintf("0.0.0.0:%d", config.App.HTTPPort), Handler: router}
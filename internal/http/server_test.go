@@ -0,0 +1,54 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// truncatedReader fails like a body whose connection closed early.
+type truncatedReader struct{}
+
+func (truncatedReader) Read([]byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestRequestBody(t *testing.T) {
+	handler := CreateServer(&config.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)), context.Background(), nil, nil).Handler
+
+	tests := []struct {
+		name   string
+		body   io.Reader
+		accept string
+		status int
+	}{
+		{"too large", strings.NewReader(strings.Repeat(" ", maxRequestBodySize+1)), "", http.StatusRequestEntityTooLarge},
+		{"too large stream", strings.NewReader(strings.Repeat(" ", maxRequestBodySize+1)), "application/x-ndjson", http.StatusRequestEntityTooLarge},
+		{"truncated", truncatedReader{}, "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/execute", tt.body)
+			request.Header.Set("Accept", tt.accept)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, tt.status, recorder.Code)
+			assert.Equal(t, "application/problem+json", recorder.Header().Get("Content-Type"))
+			var p problem
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &p))
+			assert.Equal(t, tt.status, p.Status)
+			assert.Equal(t, common.InvalidRequestCode, p.Code)
+		})
+	}
+}
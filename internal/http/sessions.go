@@ -0,0 +1,55 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// CreateSession starts a session and describes it.
+func (ca *CalculatorHTTP) CreateSession() ([]byte, error) {
+	info, err := ca.sessions.Create()
+	if err != nil {
+		ca.logger.Error(err.Error())
+		return nil, err
+	}
+	return json.Marshal(info)
+}
+
+// GetSession describes the session together with its variables.
+func (ca *CalculatorHTTP) GetSession(id string) ([]byte, error) {
+	info, err := ca.sessions.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(info)
+}
+
+// ExecuteInSession runs a request body of /execute, a program if program is
+// set, with the variables of the session available.
+func (ca *CalculatorHTTP) ExecuteInSession(
+	ctx context.Context,
+	id string,
+	data []byte,
+	program bool,
+) ([]byte, error) {
+	ca.logger.Info("Processing HTTP session request with request_id", "request_id", ctx.Value("request_id"), "session_id", id)
+	decode := ca.decode
+	if program {
+		decode = ca.decodeProgram
+	}
+	req, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	result, err := ca.sessions.Execute(ctx, id, ctx.Value("request_id").(string), req.Operations, req.Options)
+	if err != nil {
+		ca.logger.Error(err.Error())
+		return nil, err
+	}
+	return ca.respond(req, result)
+}
+
+// DeleteSession ends the session.
+func (ca *CalculatorHTTP) DeleteSession(id string) error {
+	return ca.sessions.Delete(id)
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Streaming media types of /execute responses.
+const (
+	ndjsonMediaType      = "application/x-ndjson"
+	eventStreamMediaType = "text/event-stream"
+)
+
+// Events of a streamed execution.
+const (
+	printEvent   = "print"
+	errorEvent   = "error"
+	summaryEvent = "summary"
+	// problemEvent ends a stream whose execution failed after the response
+	// had started.
+	problemEvent = "problem"
+)
+
+// streamingMediaType returns the first streaming media type the Accept
+// header lists, or "" if the client accepts none.
+func streamingMediaType(accept string) string {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if mediaType == ndjsonMediaType || mediaType == eventStreamMediaType {
+			return mediaType
+		}
+	}
+	return ""
+}
+
+// eventStream writes the events of a streamed execution and flushes every
+// one of them. The response only starts with the first event, so errors
+// found before anything is computed are still answered with a problem.
+type eventStream struct {
+	w         http.ResponseWriter
+	mediaType string
+	started   bool
+}
+
+// emit writes an NDJSON line holding an object with the event as the only
+// key or a server-sent event named after the event.
+func (s *eventStream) emit(event string, data any) error {
+	if !s.started {
+		s.w.Header().Set("Content-Type", s.mediaType)
+		s.w.Header().Set("Cache-Control", "no-cache")
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+	}
+
+	var err error
+	switch s.mediaType {
+	case eventStreamMediaType:
+		var body []byte
+		if body, err = json.Marshal(data); err == nil {
+			_, err = fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, body)
+		}
+	default:
+		var body []byte
+		if body, err = json.Marshal(map[string]any{event: data}); err == nil {
+			_, err = fmt.Fprintf(s.w, "%s\n", body)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if flusher, ok := s.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// fail reports the error of the execution: with a problem response if the
+// stream has not started and with a terminal problem event otherwise.
+func (s *eventStream) fail(err error) {
+	if !s.started {
+		writeProblem(s.w, err)
+		return
+	}
+	s.emit(problemEvent, newProblem(err))
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"upgraded-calculator/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteStreaming(t *testing.T) {
+	handler := CreateServer(&config.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)), context.Background(), nil, nil).Handler
+	body := `{"operations": [
+		{"type": "calc", "var": "x", "op": "+", "left": 1, "right": 2},
+		{"type": "calc", "var": "y", "op": "/", "left": "x", "right": 0},
+		{"type": "print", "var": "x"}
+	], "errors": "collect"}`
+
+	tests := []struct {
+		name      string
+		accept    string
+		mediaType string
+		separator string
+		want      []string
+	}{
+		{
+			name:      "ndjson",
+			accept:    "application/x-ndjson",
+			mediaType: ndjsonMediaType,
+			separator: "\n",
+			want: []string{
+				`{"error":{"index":1,"var":"y","code":"DIVISION_BY_ZERO","message":"division by zero"}}`,
+				`{"print":{"var":"x","value":3}}`,
+				`{"summary":{"computed":1,"skipped":[]}}`,
+			},
+		},
+		{
+			name:      "server-sent events",
+			accept:    "text/html, text/event-stream;q=0.9",
+			mediaType: eventStreamMediaType,
+			separator: "\n\n",
+			want: []string{
+				"event: error\ndata: {\"index\":1,\"var\":\"y\",\"code\":\"DIVISION_BY_ZERO\",\"message\":\"division by zero\"}",
+				"event: print\ndata: {\"var\":\"x\",\"value\":3}",
+				"event: summary\ndata: {\"computed\":1,\"skipped\":[]}",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/execute", strings.NewReader(body))
+			request.Header.Set("Accept", tt.accept)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, tt.mediaType, recorder.Header().Get("Content-Type"))
+			events := strings.Split(strings.TrimSuffix(recorder.Body.String(), tt.separator), tt.separator)
+			// Prints and failures come in completion order, the summary last.
+			assert.ElementsMatch(t, tt.want[:2], events[:len(events)-1])
+			assert.Equal(t, tt.want[2], events[len(events)-1])
+		})
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "/execute", strings.NewReader(`[{"type": "print", "var": "x"}]`))
+	request.Header.Set("Accept", ndjsonMediaType)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, "application/problem+json", recorder.Header().Get("Content-Type"))
+}
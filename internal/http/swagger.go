@@ -0,0 +1,56 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"upgraded-calculator/internal/common"
+)
+
+// swaggerHandler serves the swagger specification with the list of calc
+// operators taken from the operator registry, so operators registered at
+// startup are documented without editing the specification file.
+func swaggerHandler(path string, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logger.Error(err.Error())
+			http.Error(w, "swagger specification is unavailable", http.StatusInternalServerError)
+			return
+		}
+
+		var spec map[string]any
+		if err = json.Unmarshal(data, &spec); err != nil {
+			logger.Error(err.Error())
+			http.Error(w, "swagger specification is invalid", http.StatusInternalServerError)
+			return
+		}
+
+		if definitions, ok := spec["definitions"].(map[string]any); ok {
+			operators := common.Operators()
+			symbols := make([]string, 0, len(operators))
+			lines := make([]string, 0, len(operators))
+			for _, op := range operators {
+				kinds := make([]string, 0, len(op.Kinds))
+				for _, kind := range op.Kinds {
+					kinds = append(kinds, kind.String())
+				}
+				symbols = append(symbols, string(op.Symbol))
+				lines = append(lines, fmt.Sprintf("'%s' (%s): %s", op.Symbol, strings.Join(kinds, ", "), op.Description))
+			}
+			definitions["CalcAvailableOperation"] = map[string]any{
+				"type":        "string",
+				"enum":        symbols,
+				"description": "Available calculator operations. " + strings.Join(lines, "; "),
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err = json.NewEncoder(w).Encode(spec); err != nil {
+			logger.Error(err.Error())
+		}
+	}
+}
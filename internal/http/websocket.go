@@ -0,0 +1,108 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/dsl"
+)
+
+// waitingEvent reports an operation of a session waiting for a variable.
+const waitingEvent = "waiting"
+
+// maxSessionMessageSize limits the size of a message of a /ws client.
+const maxSessionMessageSize = 1 << 20
+
+// sessionMessage is a message of a /ws client: one operation or one or
+// more lines of a program.
+type sessionMessage struct {
+	Operation *common.Operation `json:"operation,omitempty"`
+	Program   *string           `json:"program,omitempty"`
+}
+
+// Session runs the operations of the messages returned by recv on one
+// calculator as they arrive and passes their results to emit, see
+// common.Session. Operations are indexed in the order they arrive across
+// messages. An invalid message is answered with a problem event and an
+// invalid operation with an error event, neither ends the session, which
+// lasts until recv fails.
+func (ca *CalculatorHTTP) Session(
+	ctx context.Context,
+	recv func() ([]byte, error),
+	emit func(event string, data any) error,
+) error {
+	ca.logger.Info("Processing HTTP session with request_id", "request_id", ctx.Value("request_id"))
+	c := common.NewUpgradedCalculator(ca.logger, ctx.Value("request_id").(string))
+	var emitErr error
+	c.Observe(func(event common.Event) {
+		if emitErr != nil {
+			return
+		}
+		switch {
+		case event.Print != nil:
+			emitErr = emit(printEvent, event.Print)
+		case event.Error != nil:
+			emitErr = emit(errorEvent, event.Error)
+		case event.Waiting != nil:
+			emitErr = emit(waitingEvent, event.Waiting)
+		}
+	})
+	session, err := common.NewSession(c, common.Options{})
+	if err != nil {
+		return err
+	}
+
+	index := 0
+	for {
+		data, err := recv()
+		if err != nil {
+			return err
+		}
+		operations, err := ca.decodeSessionMessage(data)
+		if err != nil {
+			ca.logger.Error(err.Error())
+			emitErr = emit(problemEvent, newProblem(err))
+		}
+		for _, op := range operations {
+			if err := session.Add(index, op); err != nil {
+				ca.logger.Error(err.Error(), "index", index)
+				var located *common.Error
+				if errors.As(err, &located) && located.Index != nil {
+					emitErr = emit(errorEvent, common.OperationError{
+						Index:   *located.Index,
+						Var:     located.Var,
+						Code:    located.Code,
+						Message: located.Message,
+					})
+				}
+			}
+			index++
+			if emitErr != nil {
+				break
+			}
+		}
+		if emitErr != nil {
+			return emitErr
+		}
+	}
+}
+
+func (ca *CalculatorHTTP) decodeSessionMessage(data []byte) ([]common.Operation, error) {
+	var message sessionMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		if common.CodeOf(err) == common.InternalCode {
+			err = common.NewError(common.InvalidRequestCode, "invalid message: %w", err)
+		}
+		return nil, err
+	}
+	switch {
+	case message.Operation != nil && message.Program != nil:
+		return nil, common.NewError(common.InvalidRequestCode, "operation and program cannot be used together").WithField("program")
+	case message.Operation != nil:
+		return []common.Operation{*message.Operation}, nil
+	case message.Program != nil:
+		return dsl.Parse(*message.Program)
+	}
+	return nil, common.NewError(common.InvalidRequestCode, "message must hold an operation or a program")
+}
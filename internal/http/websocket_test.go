@@ -0,0 +1,58 @@
+package http
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"upgraded-calculator/internal/config"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionWebSocket(t *testing.T) {
+	handler := CreateServer(&config.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)), context.Background(), nil, nil).Handler
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	exchange := func(message string, replies int) []string {
+		t.Helper()
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(message)))
+		var received []string
+		for range replies {
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+			_, data, err := conn.ReadMessage()
+			require.NoError(t, err)
+			received = append(received, strings.TrimSpace(string(data)))
+		}
+		return received
+	}
+
+	assert.Equal(t, []string{
+		`{"waiting":{"index":0,"var":"y","for":"x"}}`,
+		`{"waiting":{"index":1,"var":"y","for":"y"}}`,
+	}, exchange(`{"program": "y = x * 2\nprint y"}`, 2))
+	assert.Equal(t, []string{
+		`{"print":{"var":"y","value":6,"seq":1}}`,
+	}, exchange(`{"operation": {"type": "calc", "var": "x", "op": "+", "left": 1, "right": 2}}`, 1))
+	assert.Equal(t, []string{
+		`{"error":{"index":3,"var":"x","code":"DUPLICATE_ASSIGNMENT","message":"variable 'x' is already assigned"}}`,
+	}, exchange(`{"program": "x = 1"}`, 1))
+
+	problem := exchange(`{"program": "x ="}`, 1)[0]
+	assert.Contains(t, problem, `"code":"SYNTAX_ERROR"`)
+	assert.Contains(t, problem, `"diagnostics":[{"line":1,"column":4,"message":"expected an expression after '='"}]`)
+
+	assert.Equal(t, []string{
+		`{"print":{"var":"x","value":3,"seq":2}}`,
+	}, exchange(`{"program": "print x"}`, 1))
+}
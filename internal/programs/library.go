@@ -0,0 +1,169 @@
+// Package programs keeps a library of named programs. Every save adds an
+// immutable version of the program, statically validated once so that
+// executing it can only fail at run time.
+package programs
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/storage"
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// Version describes a saved version of a program.
+type Version struct {
+	Name      string    `json:"name"`
+	Version   int       `json:"version"`
+	Inputs    []string  `json:"inputs"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Library owns the saved programs and writes every new version to the
+// store.
+type Library struct {
+	logger *slog.Logger
+	store  storage.Store
+	now    func() time.Time
+
+	mu sync.RWMutex
+	// programs holds the versions of every program in order, version n at
+	// index n-1.
+	programs map[string][]storage.Program
+}
+
+func NewLibrary(logger *slog.Logger, store storage.Store) *Library {
+	return &Library{
+		logger:   logger,
+		store:    store,
+		now:      time.Now,
+		programs: make(map[string][]storage.Program),
+	}
+}
+
+// Load restores the programs saved in the store.
+func (l *Library) Load() error {
+	saved, err := l.store.LoadPrograms()
+	if err != nil {
+		return err
+	}
+	sort.Slice(saved, func(i, j int) bool {
+		if saved[i].Name != saved[j].Name {
+			return saved[i].Name < saved[j].Name
+		}
+		return saved[i].Version < saved[j].Version
+	})
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, program := range saved {
+		l.programs[program.Name] = append(l.programs[program.Name], program)
+	}
+	l.logger.Info("Programs loaded", "count", len(l.programs))
+	return nil
+}
+
+// Save validates the operations and stores them as the next version of the
+// named program. inputs lists the variables the program reads without
+// assigning them, every execution has to pass them.
+func (l *Library) Save(name string, inputs []string, operations []common.Operation) (Version, error) {
+	if !namePattern.MatchString(name) {
+		return Version{}, common.NewError(common.InvalidRequestCode, "program name must be 1 to 64 letters, digits, '_', '-' or '.'").WithField("name")
+	}
+	for i, input := range inputs {
+		if parsed, err := common.ParseOperand(input); err != nil || parsed.StringValue == nil {
+			return Version{}, common.NewError(common.InvalidRequestCode, "invalid input variable name '%s'", input).WithField("inputs")
+		}
+		if slices.Contains(inputs[:i], input) {
+			return Version{}, common.NewError(common.InvalidRequestCode, "input '%s' is listed more than once", input).WithField("inputs")
+		}
+	}
+	if len(operations) == 0 {
+		return Version{}, common.NewError(common.InvalidRequestCode, "program has no operations").WithField("operations")
+	}
+	if err := common.ValidateProgram(operations, inputs); err != nil {
+		return Version{}, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	program := storage.Program{
+		Name:       name,
+		Version:    len(l.programs[name]) + 1,
+		CreatedAt:  l.now(),
+		Inputs:     slices.Clone(inputs),
+		Operations: slices.Clone(operations),
+	}
+	if err := l.store.SaveProgram(program); err != nil {
+		l.logger.Error("Failed to save program", "name", name, "error", err)
+		return Version{}, common.NewError(common.InternalCode, "cannot save program")
+	}
+	l.programs[name] = append(l.programs[name], program)
+	l.logger.Info("Program saved", "name", name, "version", program.Version)
+	return version(program), nil
+}
+
+// Versions describes the versions of the named program, oldest first.
+func (l *Library) Versions(name string) ([]Version, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	programs, exists := l.programs[name]
+	if !exists {
+		return nil, common.NewError(common.NotFoundCode, "program '%s' does not exist", name)
+	}
+	versions := make([]Version, 0, len(programs))
+	for _, program := range programs {
+		versions = append(versions, version(program))
+	}
+	return versions, nil
+}
+
+// Execute runs the version of the named program with the options, whose
+// inputs have to be exactly the inputs of the program.
+func (l *Library) Execute(
+	ctx context.Context,
+	name string,
+	number int,
+	requestID string,
+	opts common.Options,
+) (common.Result, error) {
+	l.mu.RLock()
+	programs := l.programs[name]
+	l.mu.RUnlock()
+	if number < 1 || number > len(programs) {
+		return common.Result{}, common.NewError(common.NotFoundCode, "version %d of program '%s' does not exist", number, name)
+	}
+	program := programs[number-1]
+
+	for _, input := range program.Inputs {
+		if _, ok := opts.Inputs[input]; !ok {
+			return common.Result{}, common.NewError(common.InvalidRequestCode, "input '%s' is required", input).WithField("inputs." + input)
+		}
+	}
+	if len(opts.Inputs) > len(program.Inputs) {
+		passed := make([]string, 0, len(opts.Inputs))
+		for input := range opts.Inputs {
+			if !slices.Contains(program.Inputs, input) {
+				passed = append(passed, input)
+			}
+		}
+		sort.Strings(passed)
+		return common.Result{}, common.NewError(common.InvalidRequestCode, "program '%s' has no input '%s'", name, passed[0]).WithField("inputs." + passed[0])
+	}
+
+	c := common.NewUpgradedCalculator(l.logger, requestID)
+	return c.Run(ctx, program.Operations, opts)
+}
+
+func version(program storage.Program) Version {
+	inputs := program.Inputs
+	if inputs == nil {
+		inputs = []string{}
+	}
+	return Version{Name: program.Name, Version: program.Version, Inputs: inputs, CreatedAt: program.CreatedAt}
+}
@@ -0,0 +1,103 @@
+package programs
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLibrary(store storage.Store) *Library {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+	return NewLibrary(logger, store)
+}
+
+// invoice is a program computing the total of price and count inputs.
+func invoice() []common.Operation {
+	return []common.Operation{
+		{Type: common.ExprOperation, Var: "total", Expr: "price * count"},
+		{Type: common.PrintOperation, Var: "total"},
+	}
+}
+
+func TestLibrary_Save(t *testing.T) {
+	l := newTestLibrary(storage.NewMemoryStore())
+	program := invoice()
+
+	_, err := l.Save("invoice", nil, program)
+	var coded *common.Error
+	require.ErrorAs(t, err, &coded)
+	assert.Equal(t, common.UndefinedVariableCode, coded.Code)
+	assert.Equal(t, 0, *coded.Index)
+
+	_, err = l.Save("invoice", []string{"price", "total"}, program)
+	assert.Equal(t, common.DuplicateAssignmentCode, common.CodeOf(err))
+	_, err = l.Save("no spaces", []string{"price", "count"}, program)
+	assert.Equal(t, common.InvalidRequestCode, common.CodeOf(err))
+	_, err = l.Versions("invoice")
+	assert.Equal(t, common.NotFoundCode, common.CodeOf(err))
+
+	first, err := l.Save("invoice", []string{"price", "count"}, program)
+	require.NoError(t, err)
+	price, two := "price", int64(2)
+	second, err := l.Save("invoice", []string{"price"}, []common.Operation{
+		{
+			Type:  common.CalcOperation,
+			Var:   "total",
+			Op:    common.Mul,
+			Left:  &common.Operand{StringValue: &price},
+			Right: &common.Operand{IntValue: &two},
+		},
+		{Type: common.PrintOperation, Var: "total"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, first.Version)
+	assert.Equal(t, 2, second.Version)
+
+	versions, err := l.Versions("invoice")
+	require.NoError(t, err)
+	assert.Equal(t, []Version{first, second}, versions)
+}
+
+func TestLibrary_Execute(t *testing.T) {
+	store := storage.NewMemoryStore()
+	saved := newTestLibrary(store)
+	_, err := saved.Save("invoice", []string{"price", "count"}, invoice())
+	require.NoError(t, err)
+
+	l := newTestLibrary(store)
+	require.NoError(t, l.Load())
+	price, err := common.ParseDecimal("2.50")
+	require.NoError(t, err)
+	for count, want := range map[int64]string{1: "2.50", 3: "7.50"} {
+		result, err := l.Execute(context.Background(), "invoice", 1, "execute", common.Options{
+			Numbers: common.DecimalNumbers,
+			Inputs: map[string]common.Operand{
+				"price": {DecimalValue: &price},
+				"count": {IntValue: &count},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Items, 1)
+		assert.Equal(t, want, result.Items[0].Value.String())
+	}
+
+	_, err = l.Execute(context.Background(), "invoice", 1, "missing", common.Options{})
+	assert.Equal(t, common.InvalidRequestCode, common.CodeOf(err))
+	one := int64(1)
+	_, err = l.Execute(context.Background(), "invoice", 1, "unknown", common.Options{Inputs: map[string]common.Operand{
+		"price": {IntValue: &one}, "count": {IntValue: &one}, "other": {IntValue: &one},
+	}})
+	var coded *common.Error
+	require.ErrorAs(t, err, &coded)
+	assert.Equal(t, "inputs.other", coded.Field)
+	_, err = l.Execute(context.Background(), "invoice", 2, "not_found", common.Options{})
+	assert.Equal(t, common.NotFoundCode, common.CodeOf(err))
+}
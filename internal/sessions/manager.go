@@ -0,0 +1,310 @@
+// Package sessions keeps named calculator sessions whose variables survive
+// across requests. Every execution into a session sees the variables
+// assigned by the previous ones and cannot assign them again. Sessions are
+// written to a storage.Store whenever they change.
+package sessions
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/config"
+	"upgraded-calculator/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+// Info describes a session. Variables is only filled by Manager.Get.
+type Info struct {
+	ID        string                  `json:"id"`
+	CreatedAt time.Time               `json:"created_at"`
+	ExpiresAt time.Time               `json:"expires_at"`
+	Variables map[string]common.Value `json:"variables,omitempty"`
+}
+
+type session struct {
+	id        string
+	createdAt time.Time
+	// mu serializes the executions into the session and guards the fields
+	// below.
+	mu        sync.Mutex
+	expiresAt time.Time
+	variables map[string]common.Value
+	history   []storage.Execution
+	deleted   bool
+}
+
+// Manager owns the sessions. It expires sessions unused for the configured
+// TTL and bounds the number of sessions and of variables per session. Only
+// the last executions of a session are kept in its history.
+type Manager struct {
+	logger       *slog.Logger
+	store        storage.Store
+	ttl          time.Duration
+	maxSessions  int
+	maxVariables int
+	maxHistory   int
+	now          func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+func NewManager(config *config.Config, logger *slog.Logger, store storage.Store) *Manager {
+	return &Manager{
+		logger:       logger,
+		store:        store,
+		ttl:          config.App.SessionTTL * time.Second,
+		maxSessions:  config.App.MaxSessions,
+		maxVariables: config.App.MaxSessionVariables,
+		maxHistory:   config.App.MaxSessionHistory,
+		now:          time.Now,
+		sessions:     make(map[string]*session),
+	}
+}
+
+// Load restores the sessions saved in the store. Sessions that expired in
+// the meantime are dropped from it.
+func (m *Manager) Load() error {
+	saved, err := m.store.Load()
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	for _, snapshot := range saved {
+		if !now.Before(snapshot.ExpiresAt) {
+			if err := m.store.Delete(snapshot.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		m.sessions[snapshot.ID] = &session{
+			id:        snapshot.ID,
+			createdAt: snapshot.CreatedAt,
+			expiresAt: snapshot.ExpiresAt,
+			variables: snapshot.Variables,
+			history:   snapshot.History,
+		}
+	}
+	m.logger.Info("Sessions loaded", "count", len(m.sessions))
+	return nil
+}
+
+// Create starts an empty session.
+func (m *Manager) Create() (Info, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.sessions) >= m.maxSessions {
+		m.sweepLocked()
+		if len(m.sessions) >= m.maxSessions {
+			return Info{}, common.NewError(common.LimitExceededCode, "cannot create more than %d sessions", m.maxSessions)
+		}
+	}
+
+	now := m.now()
+	s := &session{
+		id:        uuid.New().String(),
+		createdAt: now,
+		expiresAt: now.Add(m.ttl),
+		variables: make(map[string]common.Value),
+	}
+	if err := m.store.Save(s.snapshot()); err != nil {
+		m.logger.Error("Failed to save session", "session_id", s.id, "error", err)
+		return Info{}, common.NewError(common.InternalCode, "cannot save session")
+	}
+	m.sessions[s.id] = s
+	m.logger.Info("Session created", "session_id", s.id)
+	return s.info(), nil
+}
+
+// Get describes the session together with its variables.
+func (m *Manager) Get(id string) (Info, error) {
+	s, err := m.acquire(id)
+	if err != nil {
+		return Info{}, err
+	}
+	defer s.mu.Unlock()
+	m.touch(s)
+
+	info := s.info()
+	info.Variables = make(map[string]common.Value, len(s.variables))
+	for name, value := range s.variables {
+		info.Variables[name] = value
+	}
+	return info, nil
+}
+
+// Execute runs the operations with the variables of the session defined. The
+// variables it assigns are kept only if the execution succeeds, and only if
+// the session does not exceed its variable limit with them and is saved; a
+// failed execution leaves the session unchanged. When errors are collected,
+// an execution reporting any is a failed one too, although its result is
+// returned without an error. Executions into one session run one at a time.
+func (m *Manager) Execute(
+	ctx context.Context,
+	id string,
+	requestID string,
+	operations []common.Operation,
+	opts common.Options,
+) (common.Result, error) {
+	s, err := m.acquire(id)
+	if err != nil {
+		return common.Result{}, err
+	}
+	defer s.mu.Unlock()
+	// A successful execution saves the refreshed expiry together with the
+	// variables, a failed one only refreshes it.
+	saved := false
+	defer func() {
+		if !saved {
+			m.touch(s)
+		}
+	}()
+
+	c := common.NewUpgradedCalculator(m.logger, requestID)
+	for name, value := range s.variables {
+		if err := c.Define(name, value); err != nil {
+			return common.Result{}, err
+		}
+	}
+	result, err := c.Run(ctx, operations, opts)
+	if err != nil {
+		return common.Result{}, err
+	}
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	variables := c.Variables()
+	if len(variables) > m.maxVariables {
+		return common.Result{}, common.NewError(common.LimitExceededCode, "session cannot hold more than %d variables", m.maxVariables)
+	}
+	history := append(s.history, storage.Execution{
+		RequestID:  requestID,
+		ExecutedAt: m.now(),
+		Operations: operations,
+		Options:    opts,
+	})
+	if len(history) > m.maxHistory {
+		history = history[len(history)-m.maxHistory:]
+	}
+
+	snapshot := s.snapshot()
+	snapshot.Variables = variables
+	snapshot.History = history
+	snapshot.ExpiresAt = m.now().Add(m.ttl)
+	if err := m.store.Save(snapshot); err != nil {
+		m.logger.Error("Failed to save session", "session_id", id, "error", err)
+		return common.Result{}, common.NewError(common.InternalCode, "cannot save session")
+	}
+	s.variables = variables
+	s.history = history
+	s.expiresAt = snapshot.ExpiresAt
+	saved = true
+	return result, nil
+}
+
+// Delete ends the session.
+func (m *Manager) Delete(id string) error {
+	s, err := m.acquire(id)
+	if err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s.deleted = true
+	delete(m.sessions, id)
+	m.logger.Info("Session deleted", "session_id", id)
+	if err := m.store.Delete(id); err != nil {
+		m.logger.Error("Failed to delete saved session", "session_id", id, "error", err)
+	}
+	return nil
+}
+
+// Run expires unused sessions periodically until the context is done.
+func (m *Manager) Run(ctx context.Context) {
+	interval := m.ttl / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.mu.Lock()
+			m.sweepLocked()
+			m.mu.Unlock()
+		}
+	}
+}
+
+// acquire returns the live session with the given id, locked. The caller
+// refreshes its expiry, see touch.
+func (m *Manager) acquire(id string) (*session, error) {
+	m.mu.Lock()
+	s, exists := m.sessions[id]
+	m.mu.Unlock()
+	if !exists {
+		return nil, common.NewError(common.NotFoundCode, "session '%s' does not exist", id)
+	}
+
+	s.mu.Lock()
+	if s.deleted || !m.now().Before(s.expiresAt) {
+		s.mu.Unlock()
+		return nil, common.NewError(common.NotFoundCode, "session '%s' does not exist", id)
+	}
+	return s, nil
+}
+
+// touch refreshes the expiry of the locked session and saves it, so that a
+// session that is only read survives a restart too. A failed save is only
+// logged: the session stays usable until the process exits.
+func (m *Manager) touch(s *session) {
+	s.expiresAt = m.now().Add(m.ttl)
+	if err := m.store.Save(s.snapshot()); err != nil {
+		m.logger.Error("Failed to save session", "session_id", s.id, "error", err)
+	}
+}
+
+// sweepLocked drops expired sessions. Sessions busy executing are skipped,
+// their expiry is refreshed once the execution is done anyway.
+func (m *Manager) sweepLocked() {
+	now := m.now()
+	for id, s := range m.sessions {
+		if !s.mu.TryLock() {
+			continue
+		}
+		if !now.Before(s.expiresAt) {
+			s.deleted = true
+			delete(m.sessions, id)
+			m.logger.Info("Session expired", "session_id", id)
+			if err := m.store.Delete(id); err != nil {
+				m.logger.Error("Failed to delete saved session", "session_id", id, "error", err)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *session) info() Info {
+	return Info{ID: s.id, CreatedAt: s.createdAt, ExpiresAt: s.expiresAt}
+}
+
+func (s *session) snapshot() storage.Session {
+	return storage.Session{
+		ID:        s.id,
+		CreatedAt: s.createdAt,
+		ExpiresAt: s.expiresAt,
+		Variables: s.variables,
+		History:   s.history,
+	}
+}
@@ -0,0 +1,190 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/config"
+	"upgraded-calculator/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager(maxSessions, maxVariables int, store storage.Store) *Manager {
+	logger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+	return NewManager(&config.Config{App: config.AppConfig{
+		SessionTTL:          60,
+		MaxSessions:         maxSessions,
+		MaxSessionVariables: maxVariables,
+		MaxSessionHistory:   10,
+	}}, logger, store)
+}
+
+func operations(t *testing.T, data string) []common.Operation {
+	var ops []common.Operation
+	require.NoError(t, json.Unmarshal([]byte(data), &ops))
+	return ops
+}
+
+func TestManager_Execute(t *testing.T) {
+	m := newTestManager(10, 100, storage.NewMemoryStore())
+	info, err := m.Create()
+	require.NoError(t, err)
+
+	_, err = m.Execute(context.Background(), info.ID, "first", operations(t, `[
+		{"type": "expr", "var": "x", "expr": "(1 + 2) * 3"}
+	]`), common.Options{})
+	require.NoError(t, err)
+
+	result, err := m.Execute(context.Background(), info.ID, "second", operations(t, `[
+		{"type": "calc", "var": "y", "op": "+", "left": "x", "right": 1},
+		{"type": "print", "var": "y"}
+	]`), common.Options{})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, "10", result.Items[0].Value.String())
+
+	_, err = m.Execute(context.Background(), info.ID, "duplicate", operations(t, `[
+		{"type": "calc", "var": "x", "op": "+", "left": 1, "right": 1}
+	]`), common.Options{})
+	assert.Equal(t, common.DuplicateAssignmentCode, common.CodeOf(err))
+
+	_, err = m.Execute(context.Background(), info.ID, "failed", operations(t, `[
+		{"type": "calc", "var": "z", "op": "/", "left": "y", "right": 0}
+	]`), common.Options{})
+	assert.Equal(t, common.DivisionByZeroCode, common.CodeOf(err))
+
+	result, err = m.Execute(context.Background(), info.ID, "collected", operations(t, `[
+		{"type": "calc", "var": "a", "op": "+", "left": "y", "right": 1},
+		{"type": "calc", "var": "b", "op": "/", "left": "y", "right": 0},
+		{"type": "print", "var": "a"}
+	]`), common.Options{Errors: common.CollectErrors})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	require.Len(t, result.Errors, 1)
+
+	got, err := m.Get(info.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]common.Value{
+		"x": common.NewIntValue(9),
+		"y": common.NewIntValue(10),
+	}, got.Variables)
+}
+
+func TestManager_Limits(t *testing.T) {
+	m := newTestManager(1, 1, storage.NewMemoryStore())
+	info, err := m.Create()
+	require.NoError(t, err)
+
+	_, err = m.Create()
+	assert.Equal(t, common.LimitExceededCode, common.CodeOf(err))
+
+	_, err = m.Execute(context.Background(), info.ID, "limit", operations(t, `[
+		{"type": "calc", "var": "x", "op": "+", "left": 1, "right": 1},
+		{"type": "calc", "var": "y", "op": "+", "left": 1, "right": 1}
+	]`), common.Options{})
+	assert.Equal(t, common.LimitExceededCode, common.CodeOf(err))
+
+	got, err := m.Get(info.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.Variables)
+}
+
+func TestManager_Expiry(t *testing.T) {
+	m := newTestManager(1, 100, storage.NewMemoryStore())
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	expired, err := m.Create()
+	require.NoError(t, err)
+	now = now.Add(time.Minute)
+
+	_, err = m.Get(expired.ID)
+	assert.Equal(t, common.NotFoundCode, common.CodeOf(err))
+
+	// The expired session no longer counts towards the limit.
+	info, err := m.Create()
+	require.NoError(t, err)
+	now = now.Add(30 * time.Second)
+	_, err = m.Get(info.ID)
+	require.NoError(t, err)
+	now = now.Add(59 * time.Second)
+	_, err = m.Get(info.ID)
+	require.NoError(t, err, "using a session refreshes its expiry")
+
+	require.NoError(t, m.Delete(info.ID))
+	assert.Equal(t, common.NotFoundCode, common.CodeOf(m.Delete(info.ID)))
+}
+
+func TestManager_Load(t *testing.T) {
+	store := storage.NewMemoryStore()
+	m := newTestManager(10, 100, store)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	info, err := m.Create()
+	require.NoError(t, err)
+	_, err = m.Execute(context.Background(), info.ID, "first", operations(t, `[
+		{"type": "calc", "var": "x", "op": "+", "left": 1, "right": 2}
+	]`), common.Options{})
+	require.NoError(t, err)
+	expired, err := m.Create()
+	require.NoError(t, err)
+	require.NoError(t, store.Save(storage.Session{ID: expired.ID, ExpiresAt: now}))
+
+	restarted := newTestManager(10, 100, store)
+	restarted.now = m.now
+	require.NoError(t, restarted.Load())
+
+	_, err = restarted.Get(expired.ID)
+	assert.Equal(t, common.NotFoundCode, common.CodeOf(err))
+	result, err := restarted.Execute(context.Background(), info.ID, "second", operations(t, `[
+		{"type": "print", "var": "x"}
+	]`), common.Options{})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, "3", result.Items[0].Value.String())
+
+	saved, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	require.Len(t, saved[0].History, 2)
+	assert.Equal(t, "second", saved[0].History[1].RequestID)
+}
+
+func TestManager_SavesRefreshedExpiry(t *testing.T) {
+	store := storage.NewMemoryStore()
+	m := newTestManager(10, 100, store)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	info, err := m.Create()
+	require.NoError(t, err)
+	now = now.Add(30 * time.Second)
+	_, err = m.Get(info.ID)
+	require.NoError(t, err)
+	now = now.Add(30 * time.Second)
+	_, err = m.Execute(context.Background(), info.ID, "failed", operations(t, `[
+		{"type": "calc", "var": "x", "op": "/", "left": 1, "right": 0}
+	]`), common.Options{})
+	assert.Equal(t, common.DivisionByZeroCode, common.CodeOf(err))
+
+	saved, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	assert.Equal(t, now.Add(time.Minute), saved[0].ExpiresAt)
+
+	now = now.Add(59 * time.Second)
+	restarted := newTestManager(10, 100, store)
+	restarted.now = m.now
+	require.NoError(t, restarted.Load())
+	_, err = restarted.Get(info.ID)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+	"upgraded-calculator/internal/common"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	sessionsBucket = []byte("sessions")
+	programsBucket = []byte("programs")
+)
+
+// FileStore keeps everything in an embedded bbolt database file, one JSON
+// record per session keyed by its id and one per program version keyed by
+// the name and the version.
+type FileStore struct {
+	db *bolt.DB
+}
+
+// OpenFileStore opens the database at path, creating it and its directory
+// if needed.
+func OpenFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open session storage %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{sessionsBucket, programsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &FileStore{db: db}, nil
+}
+
+// record is the stored form of a session. Values keep their kind, which
+// their JSON form used in responses does not.
+type record struct {
+	ID        string                 `json:"id"`
+	CreatedAt time.Time              `json:"created_at"`
+	ExpiresAt time.Time              `json:"expires_at"`
+	Variables map[string]storedValue `json:"variables"`
+	History   []Execution            `json:"history,omitempty"`
+}
+
+type storedValue struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+func (s *FileStore) Save(session Session) error {
+	r := record{
+		ID:        session.ID,
+		CreatedAt: session.CreatedAt,
+		ExpiresAt: session.ExpiresAt,
+		Variables: make(map[string]storedValue, len(session.Variables)),
+		History:   session.History,
+	}
+	for name, value := range session.Variables {
+		r.Variables[name] = storedValue{Kind: value.Kind().String(), Value: value.String()}
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(sessionsBucket).Put([]byte(session.ID), data)
+	})
+}
+
+func (s *FileStore) Delete(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(sessionsBucket).Delete([]byte(id))
+	})
+}
+
+func (s *FileStore) Load() ([]Session, error) {
+	var sessions []Session
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(sessionsBucket).ForEach(func(key, data []byte) error {
+			var r record
+			if err := json.Unmarshal(data, &r); err != nil {
+				return fmt.Errorf("session %s: %w", key, err)
+			}
+			session := Session{
+				ID:        r.ID,
+				CreatedAt: r.CreatedAt,
+				ExpiresAt: r.ExpiresAt,
+				Variables: make(map[string]common.Value, len(r.Variables)),
+				History:   r.History,
+			}
+			for name, stored := range r.Variables {
+				value, err := stored.value()
+				if err != nil {
+					return fmt.Errorf("session %s, variable %s: %w", key, name, err)
+				}
+				session.Variables[name] = value
+			}
+			sessions = append(sessions, session)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (s *FileStore) SaveProgram(program Program) error {
+	data, err := json.Marshal(program)
+	if err != nil {
+		return err
+	}
+	key := fmt.Appendf(nil, "%s/%d", program.Name, program.Version)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(programsBucket).Put(key, data)
+	})
+}
+
+func (s *FileStore) LoadPrograms() ([]Program, error) {
+	var programs []Program
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(programsBucket).ForEach(func(key, data []byte) error {
+			var program Program
+			if err := json.Unmarshal(data, &program); err != nil {
+				return fmt.Errorf("program %s: %w", key, err)
+			}
+			programs = append(programs, program)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return programs, nil
+}
+
+func (s *FileStore) Close() error {
+	return s.db.Close()
+}
+
+func (v storedValue) value() (common.Value, error) {
+	switch v.Kind {
+	case common.IntKind.String():
+		i, err := strconv.ParseInt(v.Value, 10, 64)
+		if err != nil {
+			return common.Value{}, err
+		}
+		return common.NewIntValue(i), nil
+	case common.BigKind.String():
+		b, ok := new(big.Int).SetString(v.Value, 10)
+		if !ok {
+			return common.Value{}, fmt.Errorf("invalid big value %q", v.Value)
+		}
+		return common.NewBigValue(b), nil
+	case common.DecimalKind.String():
+		d, err := common.ParseDecimal(v.Value)
+		if err != nil {
+			return common.Value{}, err
+		}
+		return common.NewDecimalValue(d), nil
+	case common.FloatKind.String():
+		f, err := strconv.ParseFloat(v.Value, 64)
+		if err != nil {
+			return common.Value{}, err
+		}
+		return common.NewFloatValue(f), nil
+	}
+	return common.Value{}, fmt.Errorf("unknown value kind %q", v.Kind)
+}
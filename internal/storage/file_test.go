@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"encoding/json"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+	"upgraded-calculator/internal/common"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "sessions.db")
+	store, err := OpenFileStore(path)
+	require.NoError(t, err)
+
+	var operations []common.Operation
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"type": "calc", "var": "x", "op": "+", "left": "99999999999999999999", "right": 1.50},
+		{"type": "expr", "var": "y", "expr": "x > 0 ? x : 0"},
+		{"type": "cond", "var": "z", "cond": "y", "then": 3, "else": "x"},
+		{"type": "print", "var": "z"}
+	]`), &operations))
+	bigValue, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	decimal, err := common.ParseDecimal("-2.50")
+	require.NoError(t, err)
+	now := time.Now().UTC().Truncate(time.Second)
+	session := Session{
+		ID:        "session",
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+		Variables: map[string]common.Value{
+			"i": common.NewIntValue(-7),
+			"b": common.NewBigValue(bigValue),
+			"d": common.NewDecimalValue(decimal),
+			"f": common.NewFloatValue(0.1),
+		},
+		History: []Execution{{
+			RequestID:  "request",
+			ExecutedAt: now,
+			Operations: operations,
+			Options:    common.Options{Numbers: common.DecimalNumbers},
+		}},
+	}
+	require.NoError(t, store.Save(session))
+	require.NoError(t, store.Save(Session{ID: "deleted"}))
+	require.NoError(t, store.Delete("deleted"))
+	program := Program{Name: "program", Version: 1, CreatedAt: now, Inputs: []string{"w"}, Operations: operations}
+	require.NoError(t, store.SaveProgram(program))
+	require.NoError(t, store.Close())
+
+	store, err = OpenFileStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	loaded, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, session.ID, loaded[0].ID)
+	assert.True(t, session.ExpiresAt.Equal(loaded[0].ExpiresAt))
+	for name, value := range session.Variables {
+		assert.Equal(t, value.Kind(), loaded[0].Variables[name].Kind(), name)
+		assert.Equal(t, value.String(), loaded[0].Variables[name].String(), name)
+	}
+	require.Len(t, loaded[0].History, 1)
+	assert.Equal(t, session.History[0].Operations, loaded[0].History[0].Operations)
+	assert.Equal(t, session.History[0].Options, loaded[0].History[0].Options)
+
+	programs, err := store.LoadPrograms()
+	require.NoError(t, err)
+	require.Len(t, programs, 1)
+	assert.True(t, program.CreatedAt.Equal(programs[0].CreatedAt))
+	programs[0].CreatedAt = program.CreatedAt
+	assert.Equal(t, program, programs[0])
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"maps"
+	"slices"
+	"sync"
+)
+
+// MemoryStore keeps everything in memory, it is lost on restart.
+type MemoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]Session
+	programs []Program
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: make(map[string]Session)}
+}
+
+func (s *MemoryStore) Save(session Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.ID] = clone(session)
+	return nil
+}
+
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+func (s *MemoryStore) Load() ([]Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sessions := make([]Session, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		sessions = append(sessions, clone(session))
+	}
+	return sessions, nil
+}
+
+func (s *MemoryStore) SaveProgram(program Program) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.programs = append(s.programs, program)
+	return nil
+}
+
+func (s *MemoryStore) LoadPrograms() ([]Program, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.programs), nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// clone copies the variables and history of the session so that the caller
+// and the store do not share them.
+func clone(session Session) Session {
+	session.Variables = maps.Clone(session.Variables)
+	session.History = slices.Clone(session.History)
+	return session
+}
@@ -0,0 +1,72 @@
+// Package storage persists sessions and saved programs so that they survive
+// restarts. The session manager writes a snapshot of a session every time it
+// changes, the program library writes every saved version, and both load
+// everything on startup.
+package storage
+
+import (
+	"fmt"
+	"time"
+	"upgraded-calculator/internal/common"
+	"upgraded-calculator/internal/config"
+)
+
+const (
+	MemoryBackend = "memory"
+	FileBackend   = "file"
+)
+
+// Session is the snapshot of a session.
+type Session struct {
+	ID        string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	Variables map[string]common.Value
+	// History holds the successful executions into the session, oldest
+	// first.
+	History []Execution
+}
+
+// Execution is a request executed into a session.
+type Execution struct {
+	RequestID  string             `json:"request_id"`
+	ExecutedAt time.Time          `json:"executed_at"`
+	Operations []common.Operation `json:"operations"`
+	Options    common.Options     `json:"options"`
+}
+
+// Program is a saved version of a program. Versions are immutable.
+type Program struct {
+	Name       string             `json:"name"`
+	Version    int                `json:"version"`
+	CreatedAt  time.Time          `json:"created_at"`
+	Inputs     []string           `json:"inputs,omitempty"`
+	Operations []common.Operation `json:"operations"`
+}
+
+// Store keeps the snapshots of sessions and the saved programs.
+// Implementations are safe for concurrent use.
+type Store interface {
+	// Save replaces the snapshot of the session.
+	Save(session Session) error
+	// Delete drops the snapshot of the session, if any.
+	Delete(id string) error
+	// Load returns every saved snapshot.
+	Load() ([]Session, error)
+	// SaveProgram adds the program version.
+	SaveProgram(program Program) error
+	// LoadPrograms returns every saved program version.
+	LoadPrograms() ([]Program, error)
+	Close() error
+}
+
+// Open opens the store selected by the configuration.
+func Open(config *config.Config) (Store, error) {
+	switch config.App.SessionStorage {
+	case MemoryBackend:
+		return NewMemoryStore(), nil
+	case FileBackend:
+		return OpenFileStore(config.App.SessionStoragePath)
+	}
+	return nil, fmt.Errorf("unknown session storage %q", config.App.SessionStorage)
+}